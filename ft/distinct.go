@@ -0,0 +1,164 @@
+package ft
+
+import (
+	"fmt"
+	"math"
+)
+
+type distinctByIter[T any, K comparable] struct {
+	iter Iter[T]
+	key  func(T) K
+	seen map[K]struct{}
+}
+
+func (di *distinctByIter[T, K]) Next() (T, bool) {
+	for next, ok := di.iter.Next(); ok; next, ok = di.iter.Next() {
+		k := di.key(next)
+		if _, found := di.seen[k]; found {
+			continue
+		}
+		di.seen[k] = struct{}{}
+		return next, true
+	}
+	var t T
+	return t, false
+}
+
+// Distinct returns iterator that yields only first occurrence of every element
+// it stores all yielded elements in map, so memory grows with number of unique elements
+// (check DedupConsecutive and DistinctApprox if it is a problem)
+func Distinct[T comparable](iter Iter[T]) Iter[T] {
+	return DistinctBy(iter, func(t T) T {
+		return t
+	})
+}
+
+// DistinctBy same as Distinct but elements are compared by key returned from `key` func
+func DistinctBy[T any, K comparable](iter Iter[T], key func(T) K) Iter[T] {
+	return &distinctByIter[T, K]{
+		iter: iter,
+		key:  key,
+		seen: make(map[K]struct{}),
+	}
+}
+
+type dedupConsecutiveIter[T comparable] struct {
+	iter    Iter[T]
+	prev    T
+	started bool
+}
+
+func (di *dedupConsecutiveIter[T]) Next() (T, bool) {
+	for next, ok := di.iter.Next(); ok; next, ok = di.iter.Next() {
+		if di.started && next == di.prev {
+			continue
+		}
+		di.started = true
+		di.prev = next
+		return next, true
+	}
+	var t T
+	return t, false
+}
+
+// DedupConsecutive returns iterator that skips elements equal to previous one (like `uniq` util)
+// it uses O(1) memory, so to get unique elements iterator must be sorted
+//
+//	ft.DedupConsecutive(ft.SliceIter([]int{1, 1, 2, 1})) // yields: 1 2 1
+func DedupConsecutive[T comparable](iter Iter[T]) Iter[T] {
+	return &dedupConsecutiveIter[T]{
+		iter: iter,
+	}
+}
+
+// bloomFilter is a probabilistic set
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+func newBloomFilter(expected int, fpRate float64) *bloomFilter {
+	if expected < 1 {
+		expected = 1
+	}
+	if !(fpRate > 0 && fpRate < 1) {
+		panic(fmt.Sprintf("ft: false positive rate must be in range (0, 1), got %v", fpRate))
+	}
+	n := float64(expected)
+	size := uint64(math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	if size < 64 {
+		size = 64
+	}
+	hashes := uint64(math.Round(float64(size) / n * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return &bloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// add adds key into filter and returns true if key probably was added before
+func (bf *bloomFilter) add(key string) bool {
+	h := hashString(key)
+	// double hashing: i-th hash is h1 + i*h2
+	h1, h2 := h&math.MaxUint32, h>>32|1
+	found := true
+	for i := uint64(0); i < bf.hashes; i++ {
+		bit := (h1 + i*h2) % bf.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if bf.bits[word]&mask == 0 {
+			found = false
+			bf.bits[word] |= mask
+		}
+	}
+	return found
+}
+
+// hashString returns FNV-1a hash of s with mixed bits
+func hashString(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	// splitmix64 finalizer spreads bits of short keys
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+type distinctApproxIter[T any] struct {
+	iter   Iter[T]
+	key    func(T) string
+	filter *bloomFilter
+}
+
+func (di *distinctApproxIter[T]) Next() (T, bool) {
+	for next, ok := di.iter.Next(); ok; next, ok = di.iter.Next() {
+		if !di.filter.add(di.key(next)) {
+			return next, true
+		}
+	}
+	var t T
+	return t, false
+}
+
+// DistinctApprox same as DistinctBy but uses bloom filter instead of map, so memory is fixed
+// `expected` is expected number of unique elements and `fpRate` is desired false positive rate
+// (for example 0.01). False positive means that unique element is considered as already seen and skipped,
+// duplicates are never yielded
+// panics if `fpRate` is not in range (0, 1)
+func DistinctApprox[T any](iter Iter[T], key func(T) string, expected int, fpRate float64) Iter[T] {
+	return &distinctApproxIter[T]{
+		iter:   iter,
+		key:    key,
+		filter: newBloomFilter(expected, fpRate),
+	}
+}
@@ -2,9 +2,15 @@ package ft_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"gtools/ft"
+	"gtools/opt"
+	"io"
+	"math"
 	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -462,3 +468,615 @@ func TestContains(t *testing.T) {
 	f([]int{2, 1, 3}, 1, true)
 	f([]int{2, 4, 3}, 1, false)
 }
+
+func TestStats(t *testing.T) {
+	st := ft.Stats(ft.SliceIter([]int{2, 4, 4, 4, 5, 5, 7, 9}))
+	assert.Equal(t, 8, st.Count)
+	assert.Equal(t, 40.0, st.Sum)
+	assert.Equal(t, 2, st.Min)
+	assert.Equal(t, 9, st.Max)
+	assert.InDelta(t, 5.0, st.Mean, 1e-9)
+	assert.InDelta(t, 4.0, st.Variance, 1e-9)
+	assert.InDelta(t, 2.0, st.StdDev, 1e-9)
+
+	empty := ft.Stats(ft.SliceIter([]float64{}))
+	assert.Equal(t, ft.Statistics[float64]{}, empty)
+
+	negative := ft.Stats(ft.SliceIter([]float64{-1.5, -3}))
+	assert.Equal(t, -3.0, negative.Min)
+	assert.Equal(t, -1.5, negative.Max)
+
+	// Sum does not overflow for small integer types
+	small := ft.Stats(ft.SliceIter([]int8{100, 100}))
+	assert.Equal(t, 200.0, small.Sum)
+	assert.InDelta(t, 100.0, small.Mean, 1e-9)
+}
+
+func TestFSum(t *testing.T) {
+	input := make([]float64, 0, 10001)
+	input = append(input, 1e16)
+	for i := 0; i < 10000; i++ {
+		input = append(input, 1)
+	}
+	assert.Equal(t, 1e16+10000, ft.FSum(ft.SliceIter(input)))
+	assert.NotEqual(t, 1e16+10000, ft.Sum(ft.SliceIter(input)))
+	assert.Equal(t, float32(5), ft.FSum(ft.SliceIter([]float32{}), 5))
+
+	inf := math.Inf(1)
+	assert.Equal(t, inf, ft.FSum(ft.SliceIter([]float64{inf, 1})))
+	assert.Equal(t, inf, ft.FSum(ft.SliceIter([]float64{1, inf})))
+	assert.Equal(t, -inf, ft.FSum(ft.SliceIter([]float64{1, -inf, 2})))
+	assert.Equal(t, float32(inf), ft.FSum(ft.SliceIter([]float32{1}), float32(inf)))
+	assert.True(t, math.IsNaN(ft.FSum(ft.SliceIter([]float64{inf, -inf}))))
+	assert.True(t, math.IsNaN(ft.FSum(ft.SliceIter([]float64{1, math.NaN(), 2}))))
+	assert.True(t, math.IsInf(ft.Stats(ft.SliceIter([]float64{inf, 1})).Sum, 1))
+}
+
+func TestMean(t *testing.T) {
+	assert.InDelta(t, 2.5, ft.Mean(ft.SliceIter([]int{1, 2, 3, 4})), 1e-9)
+	assert.InDelta(t, 2.5, ft.Average(ft.SliceIter([]int{1, 2, 3, 4})), 1e-9)
+	assert.Equal(t, 0.0, ft.Mean(ft.SliceIter([]int{})))
+	assert.Equal(t, 250.0, ft.Mean(ft.SliceIter([]uint8{250, 250, 250})))
+}
+
+func TestProductOf(t *testing.T) {
+	assert.Equal(t, 24, ft.ProductOf(ft.SliceIter([]int{1, 2, 3, 4})))
+	assert.Equal(t, 1, ft.ProductOf(ft.SliceIter([]int{})))
+	assert.Equal(t, 48, ft.ProductOf(ft.SliceIter([]int{1, 2, 3, 4}), 2))
+	assert.Equal(t, complex(0, 2), ft.ProductOf(ft.SliceIter([]complex128{complex(1, 1), complex(1, 1)})))
+}
+
+func TestHistogram(t *testing.T) {
+	f := func(input []int, buckets []int, expected []int) {
+		result := ft.Histogram(ft.SliceIter(input), buckets)
+		assert.Equal(t, expected, result)
+	}
+	f([]int{1, 5, 7, 20}, []int{5, 10}, []int{2, 1, 1})
+	f([]int{}, []int{5, 10}, []int{0, 0, 0})
+	f([]int{1, 2, 3}, []int{}, []int{3})
+	f([]int{-1, 0, 1}, []int{0}, []int{2, 1})
+	f([]int{1, 5}, []int{5, 5}, []int{2, 0, 0})
+	assert.Panics(t, func() {
+		ft.Histogram(ft.SliceIter([]int{1}), []int{10, 5})
+	})
+}
+
+func TestSorted(t *testing.T) {
+	f := func(input, expected []int) {
+		less := func(a, b int) bool {
+			return a < b
+		}
+		assert.Equal(t, expected, ft.Collect(ft.Sorted(ft.SliceIter(input), less)))
+		assert.Equal(t, expected, ft.Collect(ft.SortedStable(ft.SliceIter(input), less)))
+	}
+	f([]int{3, 1, 2}, []int{1, 2, 3})
+	f([]int{}, []int{})
+	f([]int{1, 1, -1}, []int{-1, 1, 1})
+}
+
+func TestSortedBy(t *testing.T) {
+	type user struct {
+		name string
+		age  int
+	}
+	input := []user{{"bob", 30}, {"alice", 25}, {"carl", 30}, {"dan", 20}}
+	result := ft.Collect(ft.SortedBy(ft.SliceIter(input), func(u user) int {
+		return u.age
+	}))
+	assert.Equal(t, []user{{"dan", 20}, {"alice", 25}, {"bob", 30}, {"carl", 30}}, result)
+}
+
+func TestSortedSpill(t *testing.T) {
+	type pair struct {
+		Key int
+		Idx int
+	}
+	f := func(input []int, maxInMemory int) {
+		pairs := make([]pair, 0, len(input))
+		for i, k := range input {
+			pairs = append(pairs, pair{Key: k, Idx: i})
+		}
+		less := func(a, b pair) bool {
+			return a.Key < b.Key
+		}
+		iter, err := ft.SortedSpill(ft.SliceIter(pairs), less, maxInMemory, ft.GobCodec[pair]())
+		assert.NoError(t, err)
+		result := ft.Collect[pair](iter)
+		assert.NoError(t, iter.Err())
+		assert.Equal(t, ft.Collect(ft.SortedStable(ft.SliceIter(pairs), less)), result)
+		_, ok := iter.Next()
+		assert.False(t, ok)
+		assert.NoError(t, iter.Err())
+	}
+	f([]int{5, 3, 8, 1, 9, 2, 7, 3, 3, 0, 6}, 3)
+	f([]int{5, 3, 8, 1}, 4)
+	f([]int{5, 3, 8, 1}, 100)
+	f([]int{}, 2)
+	f([]int{2, 1, 2, 1, 2, 1}, 1)
+}
+
+func TestSortedSpill_Close(t *testing.T) {
+	less := func(a, b int) bool {
+		return a < b
+	}
+	iter, err := ft.SortedSpill(ft.SliceIter([]int{4, 3, 2, 1}), less, 1, ft.GobCodec[int]())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ft.First[int](iter))
+	assert.NoError(t, iter.Close())
+	_, ok := iter.Next()
+	assert.False(t, ok)
+	assert.NoError(t, iter.Err())
+
+	_, err = ft.SortedSpill(ft.SliceIter([]int{1}), less, 0, ft.GobCodec[int]())
+	assert.Error(t, err)
+}
+
+func TestMergeSorted(t *testing.T) {
+	less := func(a, b int) bool {
+		return a < b
+	}
+	f := func(inputs [][]int, expected []int, expectedDedup []int) {
+		iters := make([]ft.Iter[int], 0, len(inputs))
+		dedupIters := make([]ft.Iter[int], 0, len(inputs))
+		for _, input := range inputs {
+			iters = append(iters, ft.SliceIter(input))
+			dedupIters = append(dedupIters, ft.SliceIter(input))
+		}
+		assert.Equal(t, expected, ft.Collect(ft.MergeSorted(less, iters...)))
+		assert.Equal(t, expectedDedup, ft.Collect(ft.MergeSortedDedup(less, dedupIters...)))
+	}
+	f([][]int{{1, 4, 7}, {2, 5, 8}, {3, 6, 9}}, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, []int{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	f([][]int{{1, 1, 3}, {}, {1, 2, 3}}, []int{1, 1, 1, 2, 3, 3}, []int{1, 2, 3})
+	f([][]int{}, []int{}, []int{})
+	f([][]int{{}, {}}, []int{}, []int{})
+}
+
+func TestMergeSorted_Stable(t *testing.T) {
+	type item struct {
+		key int
+		src string
+	}
+	less := func(a, b item) bool {
+		return a.key < b.key
+	}
+	iter1 := ft.SliceIter([]item{{1, "a"}, {2, "a"}})
+	iter2 := ft.SliceIter([]item{{1, "b"}, {2, "b"}})
+	result := ft.Collect(ft.MergeSorted(less, iter1, iter2))
+	assert.Equal(t, []item{{1, "a"}, {1, "b"}, {2, "a"}, {2, "b"}}, result)
+}
+
+func TestMergeSortedChecked(t *testing.T) {
+	less := func(a, b int) bool {
+		return a < b
+	}
+	iter := ft.MergeSortedChecked(less, ft.SliceIter([]int{1, 3, 5}), ft.SliceIter([]int{2, 4}))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ft.Collect[int](iter))
+	assert.NoError(t, iter.Err())
+
+	iter = ft.MergeSortedChecked(less, ft.SliceIter([]int{1, 3, 5}), ft.SliceIter([]int{2, 6, 4}))
+	assert.Equal(t, []int{1, 2, 3, 5, 6}, ft.Collect[int](iter))
+	var orderErr *ft.OutOfOrderError
+	assert.ErrorAs(t, iter.Err(), &orderErr)
+	assert.Equal(t, 1, orderErr.Source)
+	assert.Equal(t, 2, orderErr.Index)
+	assert.Equal(t, 6, orderErr.Prev)
+	assert.Equal(t, 4, orderErr.Next)
+}
+
+func TestMergeJoinBy(t *testing.T) {
+	left := ft.SliceIter([]int{1, 2, 4})
+	right := ft.SliceIter([]string{"bb", "ccc", "eeeee"})
+	result := ft.Collect(ft.MergeJoinBy(left, right, func(l int, r string) int {
+		return l - len(r)
+	}))
+	assert.Len(t, result, 5)
+	expected := []struct {
+		l int
+		r string
+	}{{1, ""}, {2, "bb"}, {0, "ccc"}, {4, ""}, {0, "eeeee"}}
+	for i, p := range result {
+		if expected[i].l != 0 {
+			assert.Equal(t, expected[i].l, *p.Left)
+		} else {
+			assert.Nil(t, p.Left)
+		}
+		if expected[i].r != "" {
+			assert.Equal(t, expected[i].r, *p.Right)
+		} else {
+			assert.Nil(t, p.Right)
+		}
+	}
+}
+
+type joinUser struct {
+	id   int
+	name string
+}
+
+type joinOrder struct {
+	userID int
+	item   string
+}
+
+func joinData() ([]joinUser, []joinOrder) {
+	users := []joinUser{{1, "alice"}, {2, "bob"}, {3, "carl"}}
+	orders := []joinOrder{{1, "book"}, {3, "pen"}, {1, "cup"}, {4, "hat"}}
+	return users, orders
+}
+
+func TestHashJoin(t *testing.T) {
+	users, orders := joinData()
+	result := ft.Collect(ft.HashJoin(ft.SliceIter(users), ft.SliceIter(orders), func(u joinUser) int {
+		return u.id
+	}, func(o joinOrder) int {
+		return o.userID
+	}))
+	assert.Equal(t, []ft.JoinPair[joinUser, joinOrder]{
+		{Left: users[0], Right: orders[0]},
+		{Left: users[0], Right: orders[2]},
+		{Left: users[2], Right: orders[1]},
+	}, result)
+}
+
+func TestLeftJoin(t *testing.T) {
+	users, orders := joinData()
+	result := ft.Collect(ft.LeftJoin(ft.SliceIter(users), ft.SliceIter(orders), func(u joinUser) int {
+		return u.id
+	}, func(o joinOrder) int {
+		return o.userID
+	}))
+	assert.Len(t, result, 4)
+	assert.Equal(t, users[0], result[0].Left)
+	assert.Equal(t, orders[0], *result[0].Right)
+	assert.Equal(t, orders[2], *result[1].Right)
+	assert.Equal(t, users[1], result[2].Left)
+	assert.Nil(t, result[2].Right)
+	assert.Equal(t, orders[1], *result[3].Right)
+}
+
+func TestSemiAntiJoin(t *testing.T) {
+	users, orders := joinData()
+	userID := func(u joinUser) int {
+		return u.id
+	}
+	orderUserID := func(o joinOrder) int {
+		return o.userID
+	}
+	semi := ft.Collect(ft.SemiJoin(ft.SliceIter(users), ft.SliceIter(orders), userID, orderUserID))
+	assert.Equal(t, []joinUser{users[0], users[2]}, semi)
+	anti := ft.Collect(ft.AntiJoin(ft.SliceIter(users), ft.SliceIter(orders), userID, orderUserID))
+	assert.Equal(t, []joinUser{users[1]}, anti)
+	empty := ft.Collect(ft.AntiJoin(ft.SliceIter(users), ft.SliceIter([]joinOrder{}), userID, orderUserID))
+	assert.Equal(t, users, empty)
+}
+
+func TestMergeJoin(t *testing.T) {
+	f := func(left []int, right []int, expected [][2]int) {
+		result := ft.Collect(ft.Map(ft.MergeJoin(ft.SliceIter(left), ft.SliceIter(right), func(l int) int {
+			return l / 10
+		}, func(r int) int {
+			return r / 10
+		}), func(p ft.JoinPair[int, int]) [2]int {
+			return [2]int{p.Left, p.Right}
+		}))
+		assert.Equal(t, expected, result)
+	}
+	f([]int{10, 20, 30}, []int{11, 31, 41}, [][2]int{{10, 11}, {30, 31}})
+	f([]int{10, 12, 20}, []int{11, 13, 25}, [][2]int{{10, 11}, {10, 13}, {12, 11}, {12, 13}, {20, 25}})
+	f([]int{}, []int{11}, [][2]int{})
+	f([]int{10}, []int{}, [][2]int{})
+	f([]int{5, 50}, []int{10, 20, 30, 40, 51, 60}, [][2]int{{50, 51}})
+}
+
+func TestDistinct(t *testing.T) {
+	f := func(input, expected []int) {
+		assert.Equal(t, expected, ft.Collect(ft.Distinct(ft.SliceIter(input))))
+	}
+	f([]int{1, 2, 1, 3, 2, 4}, []int{1, 2, 3, 4})
+	f([]int{}, []int{})
+	f([]int{1, 1, 1}, []int{1})
+}
+
+func TestDistinctBy(t *testing.T) {
+	input := []string{"one", "two", "three", "four", "five", "six"}
+	result := ft.Collect(ft.DistinctBy(ft.SliceIter(input), func(s string) int {
+		return len(s)
+	}))
+	assert.Equal(t, []string{"one", "three", "four"}, result)
+}
+
+func TestDedupConsecutive(t *testing.T) {
+	f := func(input, expected []int) {
+		assert.Equal(t, expected, ft.Collect(ft.DedupConsecutive(ft.SliceIter(input))))
+	}
+	f([]int{1, 1, 2, 1}, []int{1, 2, 1})
+	f([]int{}, []int{})
+	f([]int{0, 0, 0}, []int{0})
+	f([]int{1, 2, 3}, []int{1, 2, 3})
+}
+
+func TestDistinctApprox(t *testing.T) {
+	const n = 10000
+	input := make([]int, 0, 2*n)
+	for i := 0; i < n; i++ {
+		input = append(input, i, i)
+	}
+	result := ft.Collect(ft.DistinctApprox(ft.SliceIter(input), strconv.Itoa, n, 0.01))
+	// duplicates are never yielded
+	assert.Equal(t, result, ft.Collect(ft.Distinct(ft.SliceIter(result))))
+	// false positives rate is close to requested
+	assert.Greater(t, len(result), n*97/100)
+	assert.LessOrEqual(t, len(result), n)
+
+	for _, rate := range []float64{0, 1, 1.5, -0.1} {
+		assert.Panics(t, func() {
+			ft.DistinctApprox(ft.SliceIter(input), strconv.Itoa, n, rate)
+		})
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	f := func(inputs [][]int, expected []int) {
+		iters := make([]ft.Iter[int], 0, len(inputs))
+		for _, input := range inputs {
+			iters = append(iters, ft.SliceIter(input))
+		}
+		assert.Equal(t, expected, ft.Collect(ft.Interleave(iters...)))
+	}
+	f([][]int{{1, 2, 3}, {10, 20, 30}}, []int{1, 10, 2, 20, 3, 30})
+	f([][]int{{1, 2, 3}, {10}, {20, 30}}, []int{1, 10, 20, 2})
+	f([][]int{}, []int{})
+}
+
+func TestRoundRobin(t *testing.T) {
+	f := func(inputs [][]int, expected []int) {
+		iters := make([]ft.Iter[int], 0, len(inputs))
+		for _, input := range inputs {
+			iters = append(iters, ft.SliceIter(input))
+		}
+		assert.Equal(t, expected, ft.Collect(ft.RoundRobin(iters...)))
+	}
+	f([][]int{{1, 2, 3}, {10}, {20, 30}}, []int{1, 10, 20, 2, 30, 3})
+	f([][]int{{}, {1}, {}, {2, 3}}, []int{1, 2, 3})
+	f([][]int{}, []int{})
+}
+
+func TestIntersperse(t *testing.T) {
+	f := func(input, expected []int) {
+		assert.Equal(t, expected, ft.Collect(ft.Intersperse(ft.SliceIter(input), 0)))
+	}
+	f([]int{1, 2, 3}, []int{1, 0, 2, 0, 3})
+	f([]int{1}, []int{1})
+	f([]int{}, []int{})
+}
+
+type failingWriter struct {
+	limit int
+}
+
+func (fw *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > fw.limit {
+		n := fw.limit
+		fw.limit = 0
+		return n, io.ErrShortWrite
+	}
+	fw.limit -= len(p)
+	return len(p), nil
+}
+
+func TestJoinIter(t *testing.T) {
+	f := func(input []int, expected string) {
+		var sb strings.Builder
+		n, err := ft.JoinIter(ft.SliceIter(input), ", ", &sb)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sb.String())
+		assert.Equal(t, len(expected), n)
+		assert.Equal(t, ft.Join(input, ", "), expected)
+	}
+	f([]int{1, 2, 3}, "1, 2, 3")
+	f([]int{1}, "1")
+	f([]int{}, "")
+
+	consumed := 0
+	counted := ft.Map(ft.SliceIter([]int{1, 2, 3, 4, 5}), func(i int) int {
+		consumed++
+		return i
+	})
+	n, err := ft.JoinIter(counted, ", ", &failingWriter{limit: 4})
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+	assert.Equal(t, 4, n)
+	// "1, 2" is the last successful write, no elements are consumed after failed one
+	assert.Equal(t, 3, consumed)
+}
+
+type stringerPoint struct {
+	x, y int
+}
+
+func (p *stringerPoint) String() string {
+	return "(" + strconv.Itoa(p.x) + ";" + strconv.Itoa(p.y) + ")"
+}
+
+type namedInt int
+
+type formattedInt int
+
+func (fi formattedInt) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "<%d>", int(fi))
+}
+
+func (fi formattedInt) String() string {
+	return "not used"
+}
+
+func TestJoinIter_Format(t *testing.T) {
+	var nilPoint *stringerPoint
+	values := []any{"s", 1, int8(-2), uint64(3), 1.5, float32(0.1), true, &stringerPoint{1, 2}, nilPoint, namedInt(7), []int{1}, nil, io.EOF, formattedInt(3)}
+	expected := ft.Collect(ft.Map(ft.SliceIter(values), func(v any) string {
+		return fmt.Sprintf("%v", v)
+	}))
+	assert.Equal(t, strings.Join(expected, "|"), ft.JoinString(ft.SliceIter(values), "|"))
+	assert.Equal(t, strings.Join(expected, "|"), ft.Join(values, "|"))
+}
+
+func TestJoinIter_Options(t *testing.T) {
+	f := func(input []int, opts ft.JoinOptions[int], expected string) {
+		var sb strings.Builder
+		n, err := ft.JoinIter(ft.SliceIter(input), ", ", &sb, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sb.String())
+		assert.Equal(t, len(expected), n)
+	}
+	f([]int{1, 2, 3, 4}, ft.JoinOptions[int]{Prefix: "[", Suffix: "]", Limit: 2}, "[1, 2, … and 2 more]")
+	f([]int{1, 2}, ft.JoinOptions[int]{Prefix: "[", Suffix: "]", Limit: 2}, "[1, 2]")
+	f([]int{}, ft.JoinOptions[int]{Prefix: "[", Suffix: "]"}, "[]")
+	f([]int{1, 2, 3}, ft.JoinOptions[int]{
+		Format: func(i int) string {
+			return strconv.Itoa(i * 10)
+		},
+		Limit: 1,
+		Truncated: func(rest int) string {
+			return "+" + strconv.Itoa(rest)
+		},
+	}, "10, +2")
+
+	// endless iterator is not consumed with SkipCount
+	f(nil, ft.JoinOptions[int]{Limit: 2, SkipCount: true}, "")
+	assert.Equal(t, "1, 2, …", ft.JoinString(ft.Cycle(ft.SliceIter([]int{1, 2})), ", ", ft.JoinOptions[int]{
+		Limit:     2,
+		SkipCount: true,
+	}))
+	assert.Equal(t, "1 (more)", ft.JoinString(ft.Cycle(ft.SliceIter([]int{1})), " ", ft.JoinOptions[int]{
+		Limit:     1,
+		SkipCount: true,
+		Truncated: func(rest int) string {
+			assert.Equal(t, -1, rest)
+			return "(more)"
+		},
+	}))
+	assert.Equal(t, "<3>", ft.Join([]formattedInt{3}, ""))
+}
+
+func TestFirstLastOpt(t *testing.T) {
+	assert.Equal(t, opt.Some(1), ft.FirstOpt(ft.SliceIter([]int{1, 2, 3})))
+	assert.Equal(t, opt.None[int](), ft.FirstOpt(ft.SliceIter([]int{})))
+	assert.Equal(t, opt.Some(3), ft.LastOpt(ft.SliceIter([]int{1, 2, 3})))
+	assert.Equal(t, opt.Some(0), ft.LastOpt(ft.SliceIter([]int{0})))
+	assert.Equal(t, opt.None[int](), ft.LastOpt(ft.SliceIter([]int{})))
+}
+
+func TestNthOpt(t *testing.T) {
+	f := func(input []int, n int, expected opt.Option[int]) {
+		assert.Equal(t, expected, ft.NthOpt(ft.SliceIter(input), n))
+	}
+	f([]int{1, 2, 3}, 0, opt.Some(1))
+	f([]int{1, 2, 3}, 2, opt.Some(3))
+	f([]int{1, 2, 3}, 3, opt.None[int]())
+	f([]int{1, 2, 3}, -1, opt.None[int]())
+	f([]int{}, 0, opt.None[int]())
+}
+
+func TestFindMaxMinOpt(t *testing.T) {
+	less := func(a, b int) bool {
+		return a < b
+	}
+	even := func(i int) bool {
+		return i%2 == 0
+	}
+	assert.Equal(t, opt.Some(4), ft.FindOpt(ft.SliceIter([]int{1, 4, 3, 6}), even))
+	assert.Equal(t, opt.None[int](), ft.FindOpt(ft.SliceIter([]int{1, 3}), even))
+	assert.Equal(t, opt.Some(6), ft.MaxOpt(ft.SliceIter([]int{1, 6, 3}), less))
+	assert.Equal(t, opt.None[int](), ft.MaxOpt(ft.SliceIter([]int{}), less))
+	assert.Equal(t, opt.Some(0), ft.MinOpt(ft.SliceIter([]int{1, 0, 3}), less))
+	assert.Equal(t, opt.None[int](), ft.MinOpt(ft.SliceIter([]int{}), less))
+}
+
+func TestFilterMapOpt(t *testing.T) {
+	input := []string{"1", "x", "3", ""}
+	result := ft.Collect(ft.FilterMapOpt(ft.SliceIter(input), func(s string) opt.Option[int] {
+		v, err := strconv.Atoi(s)
+		return opt.Of(v, err == nil)
+	}))
+	assert.Equal(t, []int{1, 3}, result)
+}
+
+func TestTryMap(t *testing.T) {
+	input := []string{"1", "x", "3", "y", "5"}
+	iter := ft.TryMap(ft.SliceIter(input), strconv.Atoi)
+	assert.Equal(t, []int{1}, ft.Collect[int](iter))
+	assert.Error(t, iter.Err())
+	assert.Len(t, iter.Errors(), 1)
+	_, ok := iter.Next()
+	assert.False(t, ok)
+
+	iter = ft.TryMap(ft.SliceIter(input), strconv.Atoi, ft.SkipErrors)
+	assert.Equal(t, []int{1, 3, 5}, ft.Collect[int](iter))
+	assert.Len(t, iter.Errors(), 2)
+	assert.Equal(t, iter.Errors()[0], iter.Err())
+
+	iter = ft.TryMap(ft.SliceIter(input), strconv.Atoi, ft.JoinErrors)
+	assert.Equal(t, 9, ft.Sum[int](iter))
+	for _, err := range iter.Errors() {
+		assert.ErrorIs(t, iter.Err(), err)
+	}
+
+	iter = ft.TryMap(ft.SliceIter([]string{"1", "2"}), strconv.Atoi)
+	assert.Equal(t, []int{1, 2}, ft.Collect[int](iter))
+	assert.NoError(t, iter.Err())
+}
+
+func TestTryFilter(t *testing.T) {
+	input := []string{"1", "2", "x", "4"}
+	even := func(s string) (bool, error) {
+		v, err := strconv.Atoi(s)
+		return v%2 == 0, err
+	}
+	iter := ft.TryFilter(ft.SliceIter(input), even)
+	assert.Equal(t, []string{"2"}, ft.Collect[string](iter))
+	assert.Error(t, iter.Err())
+
+	iter = ft.TryFilter(ft.SliceIter(input), even, ft.SkipErrors)
+	assert.Equal(t, []string{"2", "4"}, ft.Collect[string](iter))
+	assert.Error(t, iter.Err())
+}
+
+func TestTryForEach(t *testing.T) {
+	errOdd := errors.New("odd")
+	f := func(policy ft.ErrorPolicy, expectedSum int, expectedErrors int) {
+		sum := 0
+		err := ft.TryForEach(ft.SliceIter([]int{2, 1, 4, 3}), func(i int) error {
+			if i%2 != 0 {
+				return fmt.Errorf("%d: %w", i, errOdd)
+			}
+			sum += i
+			return nil
+		}, policy)
+		assert.Equal(t, expectedSum, sum)
+		assert.ErrorIs(t, err, errOdd)
+		if expectedErrors > 1 {
+			assert.Contains(t, err.Error(), "1: odd")
+			assert.Contains(t, err.Error(), "3: odd")
+		}
+	}
+	f(ft.StopOnError, 2, 1)
+	f(ft.SkipErrors, 6, 1)
+	f(ft.JoinErrors, 6, 2)
+
+	err := ft.TryForEach(ft.SliceIter([]int{1, 2}), func(int) error {
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTryMap_Chain(t *testing.T) {
+	iter := ft.TryMap(ft.SliceIter([]string{"1", "2", "3", "4", "5"}), strconv.Atoi)
+	result := ft.Reduce(ft.Map(ft.Filter[int](iter, func(t int) bool {
+		return t%2 != 0
+	}), func(t int) int {
+		return t * 2
+	}), func(o string, t int) string {
+		return o + strconv.Itoa(t)
+	})
+	assert.NoError(t, iter.Err())
+	assert.Equal(t, "2610", result)
+}
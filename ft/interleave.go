@@ -0,0 +1,87 @@
+package ft
+
+type interleaveIter[T any] struct {
+	iters []Iter[T]
+	idx   int
+	// skipExhausted continues iterating over remaining iterators when one of them ends
+	skipExhausted bool
+}
+
+func (ii *interleaveIter[T]) Next() (T, bool) {
+	for len(ii.iters) > 0 {
+		if ii.idx >= len(ii.iters) {
+			ii.idx = 0
+		}
+		next, ok := ii.iters[ii.idx].Next()
+		if ok {
+			ii.idx++
+			return next, true
+		}
+		if !ii.skipExhausted {
+			ii.iters = nil
+			break
+		}
+		// remove exhausted iterator, idx now points to the next one
+		ii.iters = append(ii.iters[:ii.idx], ii.iters[ii.idx+1:]...)
+	}
+	var t T
+	return t, false
+}
+
+// Interleave returns iterator that yields elements from provided iterators in turn:
+// first element of first iter, first element of second iter, ..., second element of first iter and so on
+// it ends when one of iterators ends (check RoundRobin if you need all elements)
+func Interleave[T any](iters ...Iter[T]) Iter[T] {
+	return &interleaveIter[T]{
+		iters: iters,
+	}
+}
+
+// RoundRobin same as Interleave but skips exhausted iterators
+// it ends when all iterators are ended
+//
+//	ft.RoundRobin(ft.SliceIter([]int{1, 2, 3}), ft.SliceIter([]int{10}), ft.SliceIter([]int{20, 30}))
+//	// yields: 1 10 20 2 30 3
+func RoundRobin[T any](iters ...Iter[T]) Iter[T] {
+	return &interleaveIter[T]{
+		iters:         append([]Iter[T]{}, iters...),
+		skipExhausted: true,
+	}
+}
+
+type intersperseIter[T any] struct {
+	iter    Iter[T]
+	sep     T
+	next    T
+	hasNext bool
+	started bool
+}
+
+func (ii *intersperseIter[T]) Next() (T, bool) {
+	if !ii.started {
+		ii.started = true
+		return ii.iter.Next()
+	}
+	if ii.hasNext {
+		ii.hasNext = false
+		return ii.next, true
+	}
+	next, ok := ii.iter.Next()
+	if !ok {
+		var t T
+		return t, false
+	}
+	ii.next = next
+	ii.hasNext = true
+	return ii.sep, true
+}
+
+// Intersperse returns iterator that yields `sep` between elements of `iter`
+//
+//	ft.Intersperse(ft.SliceIter([]int{1, 2, 3}), 0) // yields: 1 0 2 0 3
+func Intersperse[T any](iter Iter[T], sep T) Iter[T] {
+	return &intersperseIter[T]{
+		iter: iter,
+		sep:  sep,
+	}
+}
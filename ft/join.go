@@ -0,0 +1,172 @@
+package ft
+
+// JoinPair is element of iterators returned by join functions
+// it contains left and right elements with equal keys
+type JoinPair[L any, R any] struct {
+	Left  L
+	Right R
+}
+
+// hashIndex lazily groups elements of `right` iterator by key
+type hashIndex[R any, K comparable] struct {
+	right Iter[R]
+	key   func(R) K
+	index map[K][]R
+}
+
+func (hi *hashIndex[R, K]) lookup(k K) []R {
+	if hi.index == nil {
+		hi.index = make(map[K][]R)
+		for next, ok := hi.right.Next(); ok; next, ok = hi.right.Next() {
+			rk := hi.key(next)
+			hi.index[rk] = append(hi.index[rk], next)
+		}
+	}
+	return hi.index[k]
+}
+
+type hashJoinIter[L any, R any, K comparable] struct {
+	left    Iter[L]
+	leftKey func(L) K
+	index   *hashIndex[R, K]
+	// outer yields left elements without matches too
+	outer   bool
+	current L
+	matches []R
+	pos     int
+}
+
+func (hj *hashJoinIter[L, R, K]) Next() (JoinPair[L, *R], bool) {
+	for hj.pos >= len(hj.matches) {
+		next, ok := hj.left.Next()
+		if !ok {
+			return JoinPair[L, *R]{}, false
+		}
+		hj.current = next
+		hj.matches = hj.index.lookup(hj.leftKey(next))
+		hj.pos = 0
+		if len(hj.matches) == 0 && hj.outer {
+			return JoinPair[L, *R]{Left: next}, true
+		}
+	}
+	r := hj.matches[hj.pos]
+	hj.pos++
+	return JoinPair[L, *R]{Left: hj.current, Right: &r}, true
+}
+
+func newHashJoinIter[L any, R any, K comparable](left Iter[L], right Iter[R], leftKey func(L) K, rightKey func(R) K, outer bool) *hashJoinIter[L, R, K] {
+	return &hashJoinIter[L, R, K]{
+		left:    left,
+		leftKey: leftKey,
+		index: &hashIndex[R, K]{
+			right: right,
+			key:   rightKey,
+		},
+		outer: outer,
+	}
+}
+
+// HashJoin returns iterator over pairs of `left` and `right` elements with equal keys (inner join)
+// on first call of Next `right` iterator is consumed and stored in hash map, `left` iterator is processed lazily
+// so pass smaller iterator as `right`. Pairs are yielded in order of `left` elements
+// and for every left element in order of matched `right` elements
+func HashJoin[L any, R any, K comparable](left Iter[L], right Iter[R], leftKey func(L) K, rightKey func(R) K) Iter[JoinPair[L, R]] {
+	return Map[JoinPair[L, *R]](newHashJoinIter(left, right, leftKey, rightKey, false), func(p JoinPair[L, *R]) JoinPair[L, R] {
+		return JoinPair[L, R]{Left: p.Left, Right: *p.Right}
+	})
+}
+
+// LeftJoin same as HashJoin but also yields `left` elements that have no matches in `right` (with nil Right)
+func LeftJoin[L any, R any, K comparable](left Iter[L], right Iter[R], leftKey func(L) K, rightKey func(R) K) Iter[JoinPair[L, *R]] {
+	return newHashJoinIter(left, right, leftKey, rightKey, true)
+}
+
+// SemiJoin returns iterator over `left` elements that have at least one match in `right`
+// every `left` element is yielded once regardless of matches count
+func SemiJoin[L any, R any, K comparable](left Iter[L], right Iter[R], leftKey func(L) K, rightKey func(R) K) Iter[L] {
+	index := &hashIndex[R, K]{right: right, key: rightKey}
+	return Filter(left, func(l L) bool {
+		return len(index.lookup(leftKey(l))) > 0
+	})
+}
+
+// AntiJoin returns iterator over `left` elements that have no matches in `right`
+func AntiJoin[L any, R any, K comparable](left Iter[L], right Iter[R], leftKey func(L) K, rightKey func(R) K) Iter[L] {
+	index := &hashIndex[R, K]{right: right, key: rightKey}
+	return Filter(left, func(l L) bool {
+		return len(index.lookup(leftKey(l))) == 0
+	})
+}
+
+type mergeJoinKeyIter[L any, R any, K Ordered] struct {
+	left      Iter[L]
+	right     Iter[R]
+	leftKey   func(L) K
+	rightKey  func(R) K
+	started   bool
+	nextRight *R
+	// group contains `right` elements with groupKey
+	group    []R
+	groupKey K
+	hasGroup bool
+	current  L
+	matches  []R
+	pos      int
+}
+
+func (mj *mergeJoinKeyIter[L, R, K]) advanceRight() {
+	mj.nextRight = nil
+	if next, ok := mj.right.Next(); ok {
+		mj.nextRight = &next
+	}
+}
+
+func (mj *mergeJoinKeyIter[L, R, K]) Next() (JoinPair[L, R], bool) {
+	if !mj.started {
+		mj.started = true
+		mj.advanceRight()
+	}
+	for mj.pos >= len(mj.matches) {
+		next, ok := mj.left.Next()
+		if !ok {
+			return JoinPair[L, R]{}, false
+		}
+		mj.current = next
+		mj.pos = 0
+		k := mj.leftKey(next)
+		if mj.hasGroup && mj.groupKey == k {
+			// same key as previous left element
+			mj.matches = mj.group
+			continue
+		}
+		for mj.nextRight != nil && mj.rightKey(*mj.nextRight) < k {
+			mj.advanceRight()
+		}
+		mj.group = mj.group[:0]
+		mj.hasGroup = false
+		for mj.nextRight != nil && mj.rightKey(*mj.nextRight) == k {
+			mj.group = append(mj.group, *mj.nextRight)
+			mj.advanceRight()
+		}
+		if len(mj.group) > 0 {
+			mj.hasGroup = true
+			mj.groupKey = k
+		}
+		mj.matches = mj.group
+	}
+	r := mj.matches[mj.pos]
+	mj.pos++
+	return JoinPair[L, R]{Left: mj.current, Right: r}, true
+}
+
+// MergeJoin same as HashJoin but for iterators sorted by key in ascending order
+// it process both iterators lazily in one pass: O(n+m) time, and memory only for `right` elements with same key
+// if iterators are not sorted some matches will be lost
+func MergeJoin[L any, R any, K Ordered](left Iter[L], right Iter[R], leftKey func(L) K, rightKey func(R) K) Iter[JoinPair[L, R]] {
+	return &mergeJoinKeyIter[L, R, K]{
+		left:     left,
+		right:    right,
+		leftKey:  leftKey,
+		rightKey: rightKey,
+	}
+}
@@ -0,0 +1,259 @@
+package ft
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+type mergeItem[T any] struct {
+	value T
+	src   int
+}
+
+type mergeHeap[T any] struct {
+	items []mergeItem[T]
+	less  func(a, b T) bool
+}
+
+func (mh *mergeHeap[T]) Len() int {
+	return len(mh.items)
+}
+
+func (mh *mergeHeap[T]) Less(i, j int) bool {
+	a, b := mh.items[i], mh.items[j]
+	if mh.less(a.value, b.value) {
+		return true
+	}
+	if mh.less(b.value, a.value) {
+		return false
+	}
+	// equal elements are taken from iterators in order they were provided
+	return a.src < b.src
+}
+
+func (mh *mergeHeap[T]) Swap(i, j int) {
+	mh.items[i], mh.items[j] = mh.items[j], mh.items[i]
+}
+
+func (mh *mergeHeap[T]) Push(x any) {
+	mh.items = append(mh.items, x.(mergeItem[T]))
+}
+
+func (mh *mergeHeap[T]) Pop() any {
+	last := mh.items[len(mh.items)-1]
+	mh.items = mh.items[:len(mh.items)-1]
+	return last
+}
+
+// OutOfOrderError returned by CheckedMergeIter.Err when one of merged iterators is not sorted
+type OutOfOrderError struct {
+	// Source is an index of unsorted iterator in provided iterators
+	Source int
+	// Index is a position of out of order element in Source iterator
+	Index int
+	// Prev and Next are the elements that are out of order (Next is less than Prev)
+	Prev any
+	Next any
+}
+
+func (e *OutOfOrderError) Error() string {
+	return fmt.Sprintf("ft: iterator %d is not sorted: element %d (%v) is less than previous (%v)", e.Source, e.Index, e.Next, e.Prev)
+}
+
+// mergeIter lazily merges sorted iterators using heap
+type mergeIter[T any] struct {
+	iters       []Iter[T]
+	heap        *mergeHeap[T]
+	initialized bool
+	// check enables validation of inputs order
+	check     bool
+	positions []int
+	err       error
+}
+
+func newMergeIter[T any](less func(a, b T) bool, iters []Iter[T]) *mergeIter[T] {
+	return &mergeIter[T]{
+		iters: iters,
+		heap: &mergeHeap[T]{
+			items: make([]mergeItem[T], 0, len(iters)),
+			less:  less,
+		},
+	}
+}
+
+func (mi *mergeIter[T]) init() {
+	mi.initialized = true
+	if mi.check {
+		mi.positions = make([]int, len(mi.iters))
+	}
+	for i, iter := range mi.iters {
+		if next, ok := iter.Next(); ok {
+			mi.heap.items = append(mi.heap.items, mergeItem[T]{value: next, src: i})
+		}
+	}
+	heap.Init(mi.heap)
+}
+
+func (mi *mergeIter[T]) Next() (T, bool) {
+	if !mi.initialized {
+		mi.init()
+	}
+	if mi.heap.Len() == 0 || mi.err != nil {
+		var t T
+		return t, false
+	}
+	top := mi.heap.items[0]
+	if next, ok := mi.iters[top.src].Next(); ok {
+		if mi.check {
+			mi.positions[top.src]++
+			if mi.heap.less(next, top.value) {
+				// top is still correctly ordered, so it is yielded and iteration stops on the next call
+				mi.err = &OutOfOrderError{
+					Source: top.src,
+					Index:  mi.positions[top.src],
+					Prev:   top.value,
+					Next:   next,
+				}
+				return top.value, true
+			}
+		}
+		mi.heap.items[0].value = next
+		heap.Fix(mi.heap, 0)
+	} else {
+		heap.Pop(mi.heap)
+	}
+	return top.value, true
+}
+
+// MergeSorted lazily merges sorted iterators into one sorted iterator
+// `less` func compare two values and return true if a < b
+// equal elements are yielded in order of provided iterators
+// if any of iterators is not sorted result is not sorted too (use MergeSortedChecked to detect it)
+func MergeSorted[T any](less func(a, b T) bool, iters ...Iter[T]) Iter[T] {
+	return newMergeIter(less, iters)
+}
+
+type dedupSortedIter[T any] struct {
+	iter    Iter[T]
+	less    func(a, b T) bool
+	prev    T
+	started bool
+}
+
+func (di *dedupSortedIter[T]) Next() (T, bool) {
+	for next, ok := di.iter.Next(); ok; next, ok = di.iter.Next() {
+		if di.started && !di.less(di.prev, next) && !di.less(next, di.prev) {
+			// equal to previous element
+			continue
+		}
+		di.started = true
+		di.prev = next
+		return next, true
+	}
+	var t T
+	return t, false
+}
+
+// MergeSortedDedup same as MergeSorted but yields only first of equal elements
+// (elements a and b are equal if !less(a, b) && !less(b, a))
+func MergeSortedDedup[T any](less func(a, b T) bool, iters ...Iter[T]) Iter[T] {
+	return &dedupSortedIter[T]{
+		iter: newMergeIter(less, iters),
+		less: less,
+	}
+}
+
+// CheckedMergeIter is iterator returned by MergeSortedChecked
+type CheckedMergeIter[T any] struct {
+	iter *mergeIter[T]
+}
+
+func (ci *CheckedMergeIter[T]) Next() (T, bool) {
+	return ci.iter.Next()
+}
+
+// Err returns *OutOfOrderError if one of merged iterators is not sorted
+// check it after iterator is exhausted
+func (ci *CheckedMergeIter[T]) Err() error {
+	return ci.iter.err
+}
+
+// MergeSortedChecked same as MergeSorted but checks that every provided iterator is sorted
+// use it for debugging: if out of order element is found iteration stops before it (all yielded elements are sorted)
+// and Err returns *OutOfOrderError
+func MergeSortedChecked[T any](less func(a, b T) bool, iters ...Iter[T]) *CheckedMergeIter[T] {
+	mi := newMergeIter(less, iters)
+	mi.check = true
+	return &CheckedMergeIter[T]{iter: mi}
+}
+
+// MergeJoinPair is element of iterator returned by MergeJoinBy
+// Left or Right is nil if there is no matching element in corresponding iterator
+type MergeJoinPair[L any, R any] struct {
+	Left  *L
+	Right *R
+}
+
+type mergeJoinIter[L any, R any] struct {
+	left      Iter[L]
+	right     Iter[R]
+	cmp       func(L, R) int
+	nextLeft  *L
+	nextRight *R
+	started   bool
+}
+
+func (mj *mergeJoinIter[L, R]) advanceLeft() {
+	mj.nextLeft = nil
+	if next, ok := mj.left.Next(); ok {
+		mj.nextLeft = &next
+	}
+}
+
+func (mj *mergeJoinIter[L, R]) advanceRight() {
+	mj.nextRight = nil
+	if next, ok := mj.right.Next(); ok {
+		mj.nextRight = &next
+	}
+}
+
+func (mj *mergeJoinIter[L, R]) Next() (MergeJoinPair[L, R], bool) {
+	if !mj.started {
+		mj.started = true
+		mj.advanceLeft()
+		mj.advanceRight()
+	}
+	l, r := mj.nextLeft, mj.nextRight
+	switch {
+	case l == nil && r == nil:
+		return MergeJoinPair[L, R]{}, false
+	case r == nil || (l != nil && mj.cmp(*l, *r) < 0):
+		mj.advanceLeft()
+		return MergeJoinPair[L, R]{Left: l}, true
+	case l == nil || mj.cmp(*l, *r) > 0:
+		mj.advanceRight()
+		return MergeJoinPair[L, R]{Right: r}, true
+	default:
+		mj.advanceLeft()
+		mj.advanceRight()
+		return MergeJoinPair[L, R]{Left: l, Right: r}, true
+	}
+}
+
+// MergeJoinBy lazily merges two sorted iterators of different types
+// `cmp` func returns negative number if l < r, positive if l > r and 0 if they are equal
+// if elements are equal they are yielded in one pair, otherwise the lesser one is yielded alone:
+//
+//	left := ft.SliceIter([]int{1, 2, 4})
+//	right := ft.SliceIter([]string{"bb", "ccc"})
+//	ft.MergeJoinBy(left, right, func(l int, r string) int {
+//		return l - len(r)
+//	})
+//	// yields: {1 nil} {2 "bb"} {nil "ccc"} {4 nil}
+func MergeJoinBy[L any, R any](left Iter[L], right Iter[R], cmp func(l L, r R) int) Iter[MergeJoinPair[L, R]] {
+	return &mergeJoinIter[L, R]{
+		left:  left,
+		right: right,
+		cmp:   cmp,
+	}
+}
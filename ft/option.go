@@ -0,0 +1,65 @@
+package ft
+
+import "gtools/opt"
+
+// FirstOpt returns first element of iter or None if iter is empty
+func FirstOpt[T any](iter Iter[T]) opt.Option[T] {
+	return opt.Of(iter.Next())
+}
+
+// LastOpt consumes iter and returns its last element or None if iter is empty
+// do not call on endless iterators
+func LastOpt[T any](iter Iter[T]) opt.Option[T] {
+	last := opt.None[T]()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		last = opt.Some(next)
+	}
+	return last
+}
+
+// NthOpt returns n-th (counting from 0) element of iter or None if iter has less elements
+func NthOpt[T any](iter Iter[T], n int) opt.Option[T] {
+	if n < 0 {
+		return opt.None[T]()
+	}
+	return FirstOpt(Skip(iter, n))
+}
+
+// FindOpt same as Find but returns Option
+func FindOpt[T any](iter Iter[T], predicate func(T) bool) opt.Option[T] {
+	return opt.Of(Find(iter, predicate))
+}
+
+// MaxOpt same as Max but returns Option
+func MaxOpt[T any](iter Iter[T], less func(a T, b T) bool) opt.Option[T] {
+	return opt.FromPtr(Max(iter, less))
+}
+
+// MinOpt same as Min but returns Option
+func MinOpt[T any](iter Iter[T], less func(a T, b T) bool) opt.Option[T] {
+	return opt.FromPtr(Min(iter, less))
+}
+
+type filterMapOptIter[T any, K any] struct {
+	iter Iter[T]
+	f    func(T) opt.Option[K]
+}
+
+func (fi *filterMapOptIter[T, K]) Next() (K, bool) {
+	for next, ok := fi.iter.Next(); ok; next, ok = fi.iter.Next() {
+		if k, some := fi.f(next).Get(); some {
+			return k, true
+		}
+	}
+	var k K
+	return k, false
+}
+
+// FilterMapOpt returns iterator that yields values of Options returned from `f` skipping None
+// it's same as Filter and Map in one call
+func FilterMapOpt[T any, K any](iter Iter[T], f func(T) opt.Option[K]) Iter[K] {
+	return &filterMapOptIter[T, K]{
+		iter: iter,
+		f:    f,
+	}
+}
@@ -0,0 +1,238 @@
+package ft
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+	"sort"
+)
+
+// Ordered is a constraint for types that supports < operator
+type Ordered interface {
+	Real | ~string
+}
+
+// Sorted consumes iter and returns iterator over its elements sorted by `less` func
+// `less` func compare two values and return true if a < b
+func Sorted[T any](iter Iter[T], less func(a, b T) bool) Iter[T] {
+	data := Collect(iter)
+	sort.Slice(data, func(i, j int) bool {
+		return less(data[i], data[j])
+	})
+	return SliceIter(data)
+}
+
+// SortedStable same as Sorted but keeps original order of equal elements
+func SortedStable[T any](iter Iter[T], less func(a, b T) bool) Iter[T] {
+	data := Collect(iter)
+	sort.SliceStable(data, func(i, j int) bool {
+		return less(data[i], data[j])
+	})
+	return SliceIter(data)
+}
+
+// SortedBy consumes iter and returns iterator over its elements sorted by key returned from `key` func
+// `key` func called once for every element. Sort is stable
+func SortedBy[T any, K Ordered](iter Iter[T], key func(T) K) Iter[T] {
+	type keyed struct {
+		key   K
+		value T
+	}
+	data := Collect(Map(iter, func(t T) keyed {
+		return keyed{key: key(t), value: t}
+	}))
+	sort.SliceStable(data, func(i, j int) bool {
+		return data[i].key < data[j].key
+	})
+	return Map(SliceIter(data), func(k keyed) T {
+		return k.value
+	})
+}
+
+// Encoder writes values into underlying writer
+type Encoder[T any] interface {
+	Encode(t T) error
+}
+
+// Decoder reads values from underlying reader
+// Decode must return io.EOF when there is no more values
+type Decoder[T any] interface {
+	Decode() (T, error)
+}
+
+// Codec used by SortedSpill to write sorted runs into temporary files and read them back
+type Codec[T any] interface {
+	NewEncoder(w io.Writer) Encoder[T]
+	NewDecoder(r io.Reader) Decoder[T]
+}
+
+type gobCodec[T any] struct{}
+
+func (gobCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return &gobEncoder[T]{enc: gob.NewEncoder(w)}
+}
+
+func (gobCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return &gobDecoder[T]{dec: gob.NewDecoder(r)}
+}
+
+type gobEncoder[T any] struct {
+	enc *gob.Encoder
+}
+
+func (ge *gobEncoder[T]) Encode(t T) error {
+	return ge.enc.Encode(t)
+}
+
+type gobDecoder[T any] struct {
+	dec *gob.Decoder
+}
+
+func (gd *gobDecoder[T]) Decode() (T, error) {
+	var t T
+	err := gd.dec.Decode(&t)
+	return t, err
+}
+
+// GobCodec returns Codec that uses encoding/gob
+func GobCodec[T any]() Codec[T] {
+	return gobCodec[T]{}
+}
+
+type runIter[T any] struct {
+	dec Decoder[T]
+	err *error
+}
+
+func (ri *runIter[T]) Next() (T, bool) {
+	next, err := ri.dec.Decode()
+	if err != nil {
+		if err != io.EOF && *ri.err == nil {
+			*ri.err = err
+		}
+		var t T
+		return t, false
+	}
+	return next, true
+}
+
+// SpillIter is iterator returned by SortedSpill
+// temporary files are removed when iterator is exhausted or Close is called
+type SpillIter[T any] struct {
+	iter  Iter[T]
+	files []*os.File
+	err   error
+	done  bool
+}
+
+func (si *SpillIter[T]) Next() (T, bool) {
+	if !si.done {
+		next, ok := si.iter.Next()
+		if ok && si.err == nil {
+			return next, true
+		}
+		si.Close()
+	}
+	var t T
+	return t, false
+}
+
+// Err returns error occurred while reading spilled runs
+// check it after iterator is exhausted
+func (si *SpillIter[T]) Err() error {
+	return si.err
+}
+
+// Close removes temporary files
+// call it if you do not consume iterator until the end
+func (si *SpillIter[T]) Close() error {
+	si.done = true
+	var err error
+	for _, f := range si.files {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		if rerr := os.Remove(f.Name()); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	si.files = nil
+	return err
+}
+
+// SortedSpill consumes iter and returns iterator over its elements sorted by `less` func (sort is stable)
+// unlike Sorted it keeps in memory at most `maxInMemory` elements:
+// every `maxInMemory` elements are sorted and written (spilled) into temporary file using `codec`
+// then returned iterator lazily merges all spilled runs
+// use it to sort iterators which does not fit into memory
+//
+//	iter, err := ft.SortedSpill(lines, func(a, b string) bool { return a < b }, 1_000_000, ft.GobCodec[string]())
+//	if err != nil {
+//		return err
+//	}
+//	defer iter.Close()
+//	ft.ForEach(iter, process)
+//	if err := iter.Err(); err != nil {
+//		return err
+//	}
+func SortedSpill[T any](iter Iter[T], less func(a, b T) bool, maxInMemory int, codec Codec[T]) (*SpillIter[T], error) {
+	if maxInMemory <= 0 {
+		return nil, errors.New("ft: maxInMemory must be positive")
+	}
+	si := &SpillIter[T]{}
+	buf := make([]T, 0, maxInMemory)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		buf = append(buf, next)
+		if len(buf) < maxInMemory {
+			continue
+		}
+		if err := si.spill(buf, less, codec); err != nil {
+			si.Close()
+			return nil, err
+		}
+		buf = buf[:0]
+	}
+	sort.SliceStable(buf, func(i, j int) bool {
+		return less(buf[i], buf[j])
+	})
+	if len(si.files) == 0 {
+		si.iter = SliceIter(buf)
+		return si, nil
+	}
+	iters := make([]Iter[T], 0, len(si.files)+1)
+	for _, f := range si.files {
+		iters = append(iters, &runIter[T]{
+			dec: codec.NewDecoder(bufio.NewReader(f)),
+			err: &si.err,
+		})
+	}
+	// remaining elements are not spilled, they are merged right from memory
+	iters = append(iters, SliceIter(buf))
+	si.iter = newMergeIter(less, iters)
+	return si, nil
+}
+
+func (si *SpillIter[T]) spill(buf []T, less func(a, b T) bool, codec Codec[T]) error {
+	sort.SliceStable(buf, func(i, j int) bool {
+		return less(buf[i], buf[j])
+	})
+	f, err := os.CreateTemp("", "ft-spill-*")
+	if err != nil {
+		return err
+	}
+	si.files = append(si.files, f)
+	w := bufio.NewWriter(f)
+	enc := codec.NewEncoder(w)
+	for _, t := range buf {
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	return err
+}
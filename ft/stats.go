@@ -0,0 +1,162 @@
+package ft
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Real is a Number without complex types (values of this types can be compared and ordered)
+type Real interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Float is a constraint for floating point types
+type Float interface {
+	~float32 | ~float64
+}
+
+// Statistics holds descriptive statistics of iterator elements calculated by Stats
+type Statistics[T Real] struct {
+	Count int
+	// Sum is float64 so it does not overflow for small integer types
+	// it is calculated with compensated summation (see FSum). Integer sums are exact only up to 2^53,
+	// bigger sums of int64/uint64 elements lose precision (use Sum with wide enough type if exact value is needed)
+	Sum  float64
+	Min  T
+	Max  T
+	Mean float64
+	// Variance is a population variance (divided by Count)
+	Variance float64
+	// StdDev is a population standard deviation
+	StdDev float64
+}
+
+// Stats consumes iter and returns its descriptive statistics in one pass
+// mean and variance calculated using Welford's algorithm so they are stable for huge iterators
+// if iter is empty returns zero Statistics (check Count field)
+func Stats[T Real](iter Iter[T]) Statistics[T] {
+	var st Statistics[T]
+	var m2 float64
+	var sum compensatedSum
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if st.Count == 0 || next < st.Min {
+			st.Min = next
+		}
+		if st.Count == 0 || next > st.Max {
+			st.Max = next
+		}
+		st.Count++
+		x := float64(next)
+		sum.add(x)
+		delta := x - st.Mean
+		st.Mean += delta / float64(st.Count)
+		m2 += delta * (x - st.Mean)
+	}
+	st.Sum = sum.result()
+	if st.Count > 0 {
+		st.Variance = m2 / float64(st.Count)
+		st.StdDev = math.Sqrt(st.Variance)
+	}
+	return st
+}
+
+// FSum consumes iter and returns sum of float elements
+// unlike Sum it uses Kahan-Babuska compensated summation so precision isn't lost on huge iterators
+// or iterators with values of different magnitude
+// infinities and NaNs are summed as usual: result is NaN if there are NaN or both +Inf and -Inf, otherwise ±Inf
+func FSum[T Float](iter Iter[T], initial ...T) T {
+	var sum compensatedSum
+	if len(initial) > 0 {
+		sum.add(float64(initial[0]))
+	}
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		sum.add(float64(next))
+	}
+	return T(sum.result())
+}
+
+// compensatedSum is a Kahan-Babuska (Neumaier) summation accumulator
+type compensatedSum struct {
+	sum, c float64
+	// special is a plain sum of non-finite values, compensation of them gives NaN (Inf - Inf)
+	special    float64
+	hasSpecial bool
+}
+
+func (cs *compensatedSum) add(x float64) {
+	if math.IsInf(x, 0) || math.IsNaN(x) {
+		cs.special += x
+		cs.hasSpecial = true
+		return
+	}
+	t := cs.sum + x
+	if math.Abs(cs.sum) >= math.Abs(x) {
+		cs.c += (cs.sum - t) + x
+	} else {
+		cs.c += (x - t) + cs.sum
+	}
+	cs.sum = t
+}
+
+func (cs *compensatedSum) result() float64 {
+	if cs.hasSpecial {
+		return cs.special
+	}
+	return cs.sum + cs.c
+}
+
+// Mean consumes iter and returns arithmetic mean of its elements (0 if iterator is empty)
+// mean calculated incrementally so it does not overflow on big integers
+func Mean[T Real](iter Iter[T]) float64 {
+	var mean float64
+	n := 0
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		n++
+		mean += (float64(next) - mean) / float64(n)
+	}
+	return mean
+}
+
+// Average same as Mean
+func Average[T Real](iter Iter[T]) float64 {
+	return Mean(iter)
+}
+
+// ProductOf consumes iter and returns product of elements
+// (named so to not conflict with cartesian Product)
+// optional argument `initial` used as first multiplier, product of empty iterator is 1
+func ProductOf[T Number](iter Iter[T], initial ...T) T {
+	var result T = 1
+	if len(initial) > 0 {
+		result = initial[0]
+	}
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		result *= next
+	}
+	return result
+}
+
+// Histogram consumes iter and counts elements per bucket
+// `buckets` are upper bounds (inclusive) of buckets sorted in ascending order, panics if they are not sorted
+// result has len(buckets)+1 elements: result[i] counts elements in range (buckets[i-1], buckets[i]]
+// and the last one counts elements greater than last bound
+//
+//	ft.Histogram(ft.SliceIter([]int{1, 5, 7, 20}), []int{5, 10}) // [2 1 1]
+func Histogram[T Real](iter Iter[T], buckets []T) []int {
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] < buckets[i-1] {
+			panic(fmt.Sprintf("ft: histogram buckets are not sorted: %v > %v at %d", buckets[i-1], buckets[i], i))
+		}
+	}
+	result := make([]int, len(buckets)+1)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		idx := sort.Search(len(buckets), func(i int) bool {
+			return next <= buckets[i]
+		})
+		result[idx]++
+	}
+	return result
+}
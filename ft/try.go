@@ -0,0 +1,134 @@
+package ft
+
+import (
+	"errors"
+
+	"gtools/result"
+)
+
+// ErrorPolicy determines how fallible functions (TryMap, TryFilter, TryForEach) handle errors
+type ErrorPolicy int
+
+const (
+	// StopOnError stops iteration on first error
+	StopOnError ErrorPolicy = iota
+	// SkipErrors skips elements on which error occurred and continue iteration
+	// Err returns first error, all of them can be get by Errors
+	SkipErrors
+	// JoinErrors same as SkipErrors but Err returns all errors joined by errors.Join
+	JoinErrors
+)
+
+// TryIter is an iterator over successful values of Results
+// errors are handled according to ErrorPolicy, check Err after iterator is exhausted
+type TryIter[T any] struct {
+	iter    Iter[result.Result[T]]
+	policy  ErrorPolicy
+	errs    []error
+	stopped bool
+}
+
+func (ti *TryIter[T]) Next() (T, bool) {
+	if !ti.stopped {
+		for next, ok := ti.iter.Next(); ok; next, ok = ti.iter.Next() {
+			v, err := next.Get()
+			if err == nil {
+				return v, true
+			}
+			ti.errs = append(ti.errs, err)
+			if ti.policy == StopOnError {
+				break
+			}
+		}
+		ti.stopped = true
+	}
+	var t T
+	return t, false
+}
+
+// Err returns error occurred during iteration (according to ErrorPolicy) or nil
+func (ti *TryIter[T]) Err() error {
+	if len(ti.errs) == 0 {
+		return nil
+	}
+	if ti.policy == JoinErrors {
+		return errors.Join(ti.errs...)
+	}
+	return ti.errs[0]
+}
+
+// Errors returns all errors occurred during iteration
+func (ti *TryIter[T]) Errors() []error {
+	return ti.errs
+}
+
+// Try returns iterator over values of successful Results
+// optional argument `policy` determines how errors are handled (StopOnError by default)
+func Try[T any](iter Iter[result.Result[T]], policy ...ErrorPolicy) *TryIter[T] {
+	ti := &TryIter[T]{
+		iter: iter,
+	}
+	if len(policy) > 0 {
+		ti.policy = policy[0]
+	}
+	return ti
+}
+
+// MapResult same as Map but `mapper` may fail, returned iterator yields Results of `mapper` calls
+func MapResult[T any, K any](iter Iter[T], mapper func(T) (K, error)) Iter[result.Result[K]] {
+	return Map(iter, func(t T) result.Result[K] {
+		return result.Of(mapper(t))
+	})
+}
+
+// TryMap same as Map but `mapper` may fail
+// optional argument `policy` determines how errors are handled (StopOnError by default)
+// check Err of returned iterator after it is consumed:
+//
+//	iter := ft.TryMap(ft.SliceIter([]string{"1", "2", "x"}), strconv.Atoi)
+//	sum := ft.Sum[int](iter)
+//	if err := iter.Err(); err != nil {
+//		return err
+//	}
+func TryMap[T any, K any](iter Iter[T], mapper func(T) (K, error), policy ...ErrorPolicy) *TryIter[K] {
+	return Try(MapResult(iter, mapper), policy...)
+}
+
+type tryFilterIter[T any] struct {
+	iter      Iter[T]
+	predicate func(T) (bool, error)
+}
+
+func (ti *tryFilterIter[T]) Next() (result.Result[T], bool) {
+	for next, ok := ti.iter.Next(); ok; next, ok = ti.iter.Next() {
+		keep, err := ti.predicate(next)
+		if err != nil {
+			return result.Err[T](err), true
+		}
+		if keep {
+			return result.Ok(next), true
+		}
+	}
+	return result.Result[T]{}, false
+}
+
+// TryFilter same as Filter but `predicate` may fail
+// optional argument `policy` determines how errors are handled (StopOnError by default)
+func TryFilter[T any](iter Iter[T], predicate func(T) (bool, error), policy ...ErrorPolicy) *TryIter[T] {
+	return Try[T](&tryFilterIter[T]{
+		iter:      iter,
+		predicate: predicate,
+	}, policy...)
+}
+
+// TryForEach same as ForEach but `f` may fail
+// optional argument `policy` determines how errors are handled (StopOnError by default)
+// returns error according to policy
+func TryForEach[T any](iter Iter[T], f func(T) error, policy ...ErrorPolicy) error {
+	ti := TryMap(iter, func(t T) (struct{}, error) {
+		return struct{}{}, f(t)
+	}, policy...)
+	for _, ok := ti.Next(); ok; _, ok = ti.Next() {
+	}
+	return ti.Err()
+}
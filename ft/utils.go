@@ -2,11 +2,14 @@ package ft
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 )
 
 // Last iterate through iter until last element and return last element
 // do not call on endless iterators
+// returns zero value if iter is empty (use LastOpt to distinguish this case)
 func Last[T any](iter Iter[T]) T {
 	next, ok := iter.Next()
 	last := next
@@ -18,6 +21,7 @@ func Last[T any](iter Iter[T]) T {
 }
 
 // First return first element of iter
+// returns zero value if iter is empty (use FirstOpt to distinguish this case)
 func First[T any](iter Iter[T]) T {
 	result, _ := iter.Next()
 	return result
@@ -27,11 +31,161 @@ func First[T any](iter Iter[T]) T {
 func Join[T any, S ~[]T](slice S, sep string) string {
 	parts := make([]string, 0, len(slice))
 	for _, s := range slice {
-		parts = append(parts, fmt.Sprintf("%v", s))
+		parts = append(parts, formatValue(s))
 	}
 	return strings.Join(parts, sep)
 }
 
+// formatValue formats t same as fmt.Sprint(t) but without reflection for common types
+func formatValue[T any](t T) string {
+	switch v := any(t).(type) {
+	case fmt.Formatter:
+		// custom Format method may handle %v in its own way
+		return fmt.Sprint(v)
+	case string:
+		return v
+	case error:
+		return callFormatter(v, v.Error)
+	case fmt.Stringer:
+		return callFormatter(v, v.String)
+	case int:
+		return strconv.Itoa(v)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// callFormatter calls String or Error method of v
+// if it panics (for example on nil pointer receiver) v is formatted by fmt that handles such cases
+func callFormatter(v any, method func() string) (s string) {
+	defer func() {
+		if recover() != nil {
+			s = fmt.Sprintf("%v", v)
+		}
+	}()
+	return method()
+}
+
+// JoinOptions is an optional argument of JoinIter and JoinString
+type JoinOptions[T any] struct {
+	// Format converts element into string. By default elements are formatted as with "%v" verb
+	Format func(T) string
+	// Prefix and Suffix are written before and after all elements
+	Prefix string
+	Suffix string
+	// Limit is maximum number of written elements (0 means no limit)
+	// if iterator has more elements they are replaced with Truncated marker.
+	// To count them the rest of iterator is consumed, set SkipCount for huge or endless iterators
+	Limit int
+	// SkipCount disables counting of elements after Limit, Truncated receives -1 in this case
+	SkipCount bool
+	// Truncated returns marker written after `Limit` elements.
+	// By default it's "… and N more" (or "…" if SkipCount is set)
+	Truncated func(rest int) string
+}
+
+// JoinIter consumes iter and writes its elements separated by `sep` into `w`
+// unlike Join it does not build slice of strings, so it can be used with huge iterators
+// optional argument `opts` configures formatting:
+//
+//	ft.JoinIter(ft.SliceIter([]int{1, 2, 3, 4}), ", ", os.Stdout, ft.JoinOptions[int]{
+//		Prefix: "[",
+//		Suffix: "]",
+//		Limit:  2,
+//	})
+//	// [1, 2, … and 2 more]
+//
+// returns number of bytes written and first write error (iteration stops on error)
+func JoinIter[T any](iter Iter[T], sep string, w io.Writer, opts ...JoinOptions[T]) (int, error) {
+	var o JoinOptions[T]
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Format == nil {
+		o.Format = formatValue[T]
+	}
+	jw := &joinWriter{w: w}
+	jw.write(o.Prefix)
+	written := 0
+	// error is checked before Next, so no element is consumed after failed write
+	for jw.err == nil {
+		next, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if written > 0 {
+			jw.write(sep)
+		}
+		if o.Limit > 0 && written == o.Limit {
+			rest := -1
+			if !o.SkipCount {
+				rest = 1 + Count(iter)
+			}
+			switch {
+			case o.Truncated != nil:
+				jw.write(o.Truncated(rest))
+			case rest < 0:
+				jw.write("…")
+			default:
+				jw.write("… and " + strconv.Itoa(rest) + " more")
+			}
+			break
+		}
+		jw.write(o.Format(next))
+		written++
+	}
+	jw.write(o.Suffix)
+	return jw.n, jw.err
+}
+
+// JoinString same as JoinIter but returns result string
+func JoinString[T any](iter Iter[T], sep string, opts ...JoinOptions[T]) string {
+	var sb strings.Builder
+	// strings.Builder never returns error
+	_, _ = JoinIter(iter, sep, &sb, opts...)
+	return sb.String()
+}
+
+// joinWriter remembers first error, so JoinIter does not check it after every write
+type joinWriter struct {
+	w   io.Writer
+	n   int
+	err error
+}
+
+func (jw *joinWriter) write(s string) {
+	if jw.err != nil || s == "" {
+		return
+	}
+	n, err := io.WriteString(jw.w, s)
+	jw.n += n
+	jw.err = err
+}
+
 func Contains[T comparable](iter Iter[T], elem T) bool {
 	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
 		if elem == next {
@@ -1,6 +1,6 @@
 module gtools
 
-go 1.18
+go 1.20
 
 require github.com/stretchr/testify v1.7.0
 
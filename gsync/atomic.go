@@ -0,0 +1,88 @@
+package gsync
+
+import "sync/atomic"
+
+// Atomic is a value of type T that can be loaded and stored atomically without locks
+// zero Atomic holds zero value of T, but CompareAndSwap requires equality func (use NewAtomic)
+type Atomic[T any] struct {
+	ptr   atomic.Pointer[T]
+	equal func(a, b T) bool
+}
+
+// NewAtomic returns Atomic with initial value `t`
+// `equal` func is used by CompareAndSwap to compare values
+func NewAtomic[T any](t T, equal func(a, b T) bool) *Atomic[T] {
+	a := &Atomic[T]{
+		equal: equal,
+	}
+	a.ptr.Store(&t)
+	return a
+}
+
+// NewComparableAtomic same as NewAtomic but values are compared with == operator
+func NewComparableAtomic[T comparable](t T) *Atomic[T] {
+	return NewAtomic(t, func(a, b T) bool {
+		return a == b
+	})
+}
+
+func (a *Atomic[T]) load() (*T, T) {
+	p := a.ptr.Load()
+	if p == nil {
+		var t T
+		return nil, t
+	}
+	return p, *p
+}
+
+// Load returns current value
+func (a *Atomic[T]) Load() T {
+	_, t := a.load()
+	return t
+}
+
+// Store sets value to `t`
+func (a *Atomic[T]) Store(t T) {
+	a.ptr.Store(&t)
+}
+
+// Swap sets value to `t` and returns previous value
+func (a *Atomic[T]) Swap(t T) T {
+	old := a.ptr.Swap(&t)
+	if old == nil {
+		var zero T
+		return zero
+	}
+	return *old
+}
+
+// CompareAndSwap sets value to `new` if current value is equal to `old`
+// returns true if value was swapped
+func (a *Atomic[T]) CompareAndSwap(old, new T) bool {
+	if a.equal == nil {
+		panic("gsync: CompareAndSwap called on Atomic without equal func")
+	}
+	newPtr := &new
+	for {
+		p, current := a.load()
+		if !a.equal(current, old) {
+			return false
+		}
+		if a.ptr.CompareAndSwap(p, newPtr) {
+			return true
+		}
+		// value was replaced (maybe with equal one), try again
+	}
+}
+
+// Update atomically replaces value with result of `f` called on current value and returns new value
+// `f` may be called several times if value is changed concurrently, so it must not have side effects
+func (a *Atomic[T]) Update(f func(T) T) T {
+	for {
+		p, current := a.load()
+		next := f(current)
+		if a.ptr.CompareAndSwap(p, &next) {
+			return next
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package gsync_test
+
+import (
+	"gtools/gsync"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomic_Simple(t *testing.T) {
+	a := gsync.NewComparableAtomic(123)
+	assert.Equal(t, 123, a.Load())
+	a.Store(42)
+	assert.Equal(t, 42, a.Load())
+	assert.Equal(t, 42, a.Swap(1))
+	assert.Equal(t, 1, a.Load())
+	assert.False(t, a.CompareAndSwap(42, 2))
+	assert.Equal(t, 1, a.Load())
+	assert.True(t, a.CompareAndSwap(1, 2))
+	assert.Equal(t, 2, a.Load())
+	assert.Equal(t, 4, a.Update(func(v int) int {
+		return v * 2
+	}))
+	assert.Equal(t, 4, a.Load())
+}
+
+func TestAtomic_Zero(t *testing.T) {
+	var a gsync.Atomic[string]
+	assert.Equal(t, "", a.Load())
+	assert.Equal(t, "", a.Swap("a"))
+	assert.Equal(t, "a", a.Load())
+	assert.Panics(t, func() {
+		a.CompareAndSwap("a", "b")
+	})
+
+	var b gsync.Atomic[int]
+	assert.Equal(t, 1, b.Update(func(v int) int {
+		return v + 1
+	}))
+}
+
+func TestAtomic_CustomEqual(t *testing.T) {
+	a := gsync.NewAtomic([]int{1, 2}, func(a, b []int) bool {
+		return len(a) == len(b)
+	})
+	assert.True(t, a.CompareAndSwap([]int{0, 0}, []int{3}))
+	assert.Equal(t, []int{3}, a.Load())
+	assert.False(t, a.CompareAndSwap([]int{0, 0}, []int{4}))
+}
+
+func TestAtomic_Concurrent(t *testing.T) {
+	const goroutines, iterations = 8, 1000
+	a := gsync.NewComparableAtomic(0)
+	casCounter := gsync.NewComparableAtomic(0)
+	wg := sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				a.Update(func(v int) int {
+					return v + 1
+				})
+				for {
+					v := casCounter.Load()
+					if casCounter.CompareAndSwap(v, v+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, goroutines*iterations, a.Load())
+	assert.Equal(t, goroutines*iterations, casCounter.Load())
+}
+
+func BenchmarkAtomic_Load(b *testing.B) {
+	a := gsync.NewComparableAtomic(42)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = a.Load()
+		}
+	})
+}
+
+func BenchmarkMutex_Load(b *testing.B) {
+	m := gsync.NewMutex(42)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = *m.Lock()
+			m.Unlock()
+		}
+	})
+}
+
+func BenchmarkAtomic_Update(b *testing.B) {
+	a := gsync.NewComparableAtomic(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			a.Update(func(v int) int {
+				return v + 1
+			})
+		}
+	})
+}
+
+func BenchmarkMutex_Update(b *testing.B) {
+	m := gsync.NewMutex(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			*m.Lock()++
+			m.Unlock()
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package gsync
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DeadlockKind is a kind of problem found by deadlock detector
+type DeadlockKind int
+
+const (
+	// RecursiveLock is reported when goroutine locks mutex it already holds
+	// (except recursive read locks of RWMutex that deadlock only if writer waits between them)
+	RecursiveLock DeadlockKind = iota
+	// LockOrderInversion is reported when mutexes are locked in order opposite to already seen one
+	// (A then B in one place and B then A in another), such code may deadlock
+	LockOrderInversion
+)
+
+func (k DeadlockKind) String() string {
+	switch k {
+	case RecursiveLock:
+		return "recursive lock"
+	case LockOrderInversion:
+		return "lock order inversion"
+	default:
+		return fmt.Sprintf("DeadlockKind(%d)", int(k))
+	}
+}
+
+// DeadlockReport describes potential deadlock found by detector
+type DeadlockReport struct {
+	Kind DeadlockKind
+	// Message describes involved locks
+	Message string
+	// Stack is a stack trace of goroutine that tries to acquire the lock
+	Stack []byte
+	// PrevStack is a stack trace where conflicting lock was acquired
+	// (for LockOrderInversion it's where opposite lock order was seen first)
+	PrevStack []byte
+}
+
+func (r DeadlockReport) String() string {
+	return fmt.Sprintf("gsync: potential deadlock (%s): %s\n\ncurrent goroutine:\n%s\nprevious acquisition:\n%s", r.Kind, r.Message, r.Stack, r.PrevStack)
+}
+
+var deadlockReporter = struct {
+	sync.Mutex
+	f func(DeadlockReport)
+}{}
+
+// SetDeadlockReporter sets func called by deadlock detector when potential deadlock is found
+// by default detector panics with report
+// detector is enabled only when package is built with `gsync_deadlock` tag (see DeadlockDetection)
+func SetDeadlockReporter(f func(DeadlockReport)) {
+	deadlockReporter.Lock()
+	defer deadlockReporter.Unlock()
+	deadlockReporter.f = f
+}
+
+func reportDeadlock(r DeadlockReport) {
+	deadlockReporter.Lock()
+	f := deadlockReporter.f
+	deadlockReporter.Unlock()
+	if f == nil {
+		panic(r.String())
+	}
+	f(r)
+}
@@ -0,0 +1,188 @@
+//go:build gsync_deadlock
+
+package gsync
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync"
+)
+
+// DeadlockDetection is true when package is built with `gsync_deadlock` tag
+// in this case all locks and unlocks of Mutex and RWMutex are tracked to find potential deadlocks
+// (acquisitions that can not wait forever such as TryLock or LockCtx are tracked but not checked,
+// read lock acquired while holding other read lock is not checked either)
+const DeadlockDetection = true
+
+type heldLock struct {
+	id    uint64
+	kind  LockKind
+	stack []byte
+}
+
+// lockEdge means that lock `to` was acquired while `from` was held
+type lockEdge struct {
+	stack []byte
+}
+
+var detector = struct {
+	sync.Mutex
+	// held locks of every goroutine
+	held map[int64][]heldLock
+	// order graph: edges[from][to]
+	edges map[uint64]map[uint64]lockEdge
+}{
+	held:  make(map[int64][]heldLock),
+	edges: make(map[uint64]map[uint64]lockEdge),
+}
+
+// lockTracker removes lock from order graph when lock is garbage collected,
+// so graph does not grow with every lock ever created.
+// It is a separate object because finalizer is not run for rwLock that references itself via waiters list
+type lockTracker struct {
+	id uint64
+	// objects smaller than 16 bytes may share tiny allocator block and never be finalized
+	_ [8]byte
+}
+
+func newLockTracker(id uint64) *lockTracker {
+	t := &lockTracker{id: id}
+	runtime.SetFinalizer(t, func(t *lockTracker) {
+		forgetLock(t.id)
+	})
+	return t
+}
+
+// forgetLock removes all edges of lock `id` from order graph
+func forgetLock(id uint64) {
+	detector.Lock()
+	defer detector.Unlock()
+	delete(detector.edges, id)
+	for from, to := range detector.edges {
+		delete(to, id)
+		if len(to) == 0 {
+			delete(detector.edges, from)
+		}
+	}
+}
+
+// goroutineID parses id of current goroutine from its stack trace
+func goroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	// "goroutine 123 [running]:..."
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseInt(string(buf), 10, 64)
+	return id
+}
+
+// path returns ids of locks on path from `from` to `to` in order graph (including both ends)
+// or nil if `to` is not reachable. Must be called with detector locked
+func path(from, to uint64, visited map[uint64]bool) []uint64 {
+	if from == to {
+		return []uint64{to}
+	}
+	visited[from] = true
+	for next := range detector.edges[from] {
+		if visited[next] {
+			continue
+		}
+		if p := path(next, to, visited); p != nil {
+			return append([]uint64{from}, p...)
+		}
+	}
+	return nil
+}
+
+func detectBeforeLock(id uint64, kind LockKind) {
+	stack := debug.Stack()
+	var reports []DeadlockReport
+	detector.Lock()
+	for _, h := range detector.held[goroutineID()] {
+		if h.id == id {
+			if kind == Shared && h.kind == Shared {
+				// recursive read lock deadlocks only if writer waits between acquisitions
+				continue
+			}
+			reports = append(reports, DeadlockReport{
+				Kind:      RecursiveLock,
+				Message:   fmt.Sprintf("%s lock %d is acquired while it is already held (%s) by current goroutine", kind, id, h.kind),
+				Stack:     stack,
+				PrevStack: h.stack,
+			})
+			continue
+		}
+		if kind == Shared && h.kind == Shared {
+			// readers do not exclude each other, such order is not recorded
+			continue
+		}
+		if _, ok := detector.edges[h.id][id]; ok {
+			// this order is already known
+			continue
+		}
+		if p := path(id, h.id, make(map[uint64]bool)); p != nil {
+			// stack of the first acquisition on the opposite path
+			prev := detector.edges[p[0]][p[1]].stack
+			reports = append(reports, DeadlockReport{
+				Kind:      LockOrderInversion,
+				Message:   fmt.Sprintf("%s lock %d is acquired while holding lock %d, but opposite order %v was seen before", kind, id, h.id, p),
+				Stack:     stack,
+				PrevStack: prev,
+			})
+		}
+		if detector.edges[h.id] == nil {
+			detector.edges[h.id] = make(map[uint64]lockEdge)
+		}
+		detector.edges[h.id][id] = lockEdge{stack: stack}
+	}
+	detector.Unlock()
+	for _, r := range reports {
+		reportDeadlock(r)
+	}
+}
+
+func detectLocked(id uint64, kind LockKind) {
+	stack := debug.Stack()
+	gid := goroutineID()
+	detector.Lock()
+	defer detector.Unlock()
+	detector.held[gid] = append(detector.held[gid], heldLock{id: id, kind: kind, stack: stack})
+}
+
+func detectUnlocked(id uint64) {
+	gid := goroutineID()
+	detector.Lock()
+	defer detector.Unlock()
+	if removeHeld(gid, id) {
+		return
+	}
+	// lock may be released by other goroutine
+	for other := range detector.held {
+		if removeHeld(other, id) {
+			return
+		}
+	}
+}
+
+// removeHeld removes last acquisition of lock `id` by goroutine `gid`. Must be called with detector locked
+func removeHeld(gid int64, id uint64) bool {
+	held := detector.held[gid]
+	for i := len(held) - 1; i >= 0; i-- {
+		if held[i].id == id {
+			held = append(held[:i], held[i+1:]...)
+			if len(held) == 0 {
+				delete(detector.held, gid)
+			} else {
+				detector.held[gid] = held
+			}
+			return true
+		}
+	}
+	return false
+}
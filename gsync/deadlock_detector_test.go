@@ -0,0 +1,49 @@
+//go:build gsync_deadlock
+
+package gsync
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// edgesOf returns number of order graph edges that start or end at lock `id`
+func edgesOf(id uint64) int {
+	detector.Lock()
+	defer detector.Unlock()
+	n := len(detector.edges[id])
+	for from, to := range detector.edges {
+		if _, ok := to[id]; ok && from != id {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDetector_ForgetsCollectedLocks(t *testing.T) {
+	keep := NewMutex(0)
+	var ids []uint64
+	for i := 0; i < 10; i++ {
+		m := NewMutex(0)
+		ids = append(ids, m.mutex.id)
+		keep.Lock()
+		m.Lock()
+		m.Unlock()
+		keep.Unlock()
+	}
+	assert.Equal(t, 10, edgesOf(keep.mutex.id))
+
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		for _, id := range ids {
+			if edgesOf(id) != 0 {
+				return false
+			}
+		}
+		return edgesOf(keep.mutex.id) == 0
+	}, time.Second, 10*time.Millisecond)
+	runtime.KeepAlive(keep)
+}
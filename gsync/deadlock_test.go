@@ -0,0 +1,173 @@
+//go:build gsync_deadlock
+
+package gsync_test
+
+import (
+	"gtools/gsync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectReports sets reporter that stores reports and panics to not block on real deadlock
+func collectReports(t *testing.T) *[]gsync.DeadlockReport {
+	reports := &[]gsync.DeadlockReport{}
+	gsync.SetDeadlockReporter(func(r gsync.DeadlockReport) {
+		*reports = append(*reports, r)
+		panic(r.Kind.String())
+	})
+	t.Cleanup(func() {
+		gsync.SetDeadlockReporter(nil)
+	})
+	return reports
+}
+
+func TestDeadlock_Inversion(t *testing.T) {
+	reports := collectReports(t)
+	a := gsync.NewMutex(0)
+	b := gsync.NewRWMutex(0)
+
+	a.Lock()
+	b.Lock()
+	b.Unlock()
+	a.Unlock()
+	assert.Empty(t, *reports)
+
+	b.RLock()
+	assert.Panics(t, func() {
+		a.Lock()
+	})
+	b.RUnlock()
+	require.Len(t, *reports, 1)
+	r := (*reports)[0]
+	assert.Equal(t, gsync.LockOrderInversion, r.Kind)
+	assert.Contains(t, string(r.Stack), "TestDeadlock_Inversion")
+	assert.Contains(t, string(r.PrevStack), "TestDeadlock_Inversion")
+
+	// lock is not acquired after report
+	_, ok := a.TryLock()
+	assert.True(t, ok)
+	a.Unlock()
+}
+
+func TestDeadlock_TransitiveInversion(t *testing.T) {
+	reports := collectReports(t)
+	a, b, c := gsync.NewMutex(0), gsync.NewMutex(0), gsync.NewMutex(0)
+	a.WithLock(func(*int) {
+		b.WithLock(func(*int) {})
+	})
+	b.WithLock(func(*int) {
+		c.WithLock(func(*int) {})
+	})
+	assert.Empty(t, *reports)
+	c.Lock()
+	assert.Panics(t, func() {
+		a.Lock()
+	})
+	c.Unlock()
+	require.Len(t, *reports, 1)
+	assert.Equal(t, gsync.LockOrderInversion, (*reports)[0].Kind)
+	assert.NotEmpty(t, (*reports)[0].PrevStack)
+	assert.NotEmpty(t, (*reports)[0].Stack)
+}
+
+func TestDeadlock_InversionInDifferentGoroutines(t *testing.T) {
+	reports := collectReports(t)
+	a, b := gsync.NewMutex(0), gsync.NewMutex(0)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.Lock()
+		b.Lock()
+		b.Unlock()
+		a.Unlock()
+	}()
+	<-done
+	b.Lock()
+	assert.Panics(t, func() {
+		a.Lock()
+	})
+	b.Unlock()
+	require.Len(t, *reports, 1)
+	assert.Equal(t, gsync.LockOrderInversion, (*reports)[0].Kind)
+}
+
+func TestDeadlock_Recursive(t *testing.T) {
+	reports := collectReports(t)
+	m := gsync.NewMutex(0)
+	m.Lock()
+	assert.Panics(t, func() {
+		m.Lock()
+	})
+	m.Unlock()
+
+	rw := gsync.NewRWMutex(0)
+	rw.RLock()
+	assert.Panics(t, func() {
+		rw.Lock()
+	})
+	rw.RUnlock()
+
+	require.Len(t, *reports, 2)
+	assert.Equal(t, gsync.RecursiveLock, (*reports)[0].Kind)
+	assert.Equal(t, gsync.RecursiveLock, (*reports)[1].Kind)
+	assert.Contains(t, string((*reports)[0].PrevStack), "TestDeadlock_Recursive")
+}
+
+func TestDeadlock_UnlockInOtherGoroutine(t *testing.T) {
+	reports := collectReports(t)
+	a, b := gsync.NewMutex(0), gsync.NewMutex(0)
+	a.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.Unlock()
+	}()
+	<-done
+	// a is not held anymore, so there is no order a -> b
+	b.Lock()
+	b.Unlock()
+	b.Lock()
+	a.Lock()
+	a.Unlock()
+	b.Unlock()
+	assert.Empty(t, *reports)
+}
+
+func TestDeadlock_ReadLocksInOppositeOrder(t *testing.T) {
+	reports := collectReports(t)
+	a, b := gsync.NewRWMutex(0), gsync.NewRWMutex(0)
+	a.RLock()
+	b.RLock()
+	b.RUnlock()
+	a.RUnlock()
+
+	b.RLock()
+	a.RLock()
+	a.RUnlock()
+	b.RUnlock()
+	assert.Empty(t, *reports)
+
+	// order of exclusive acquisitions is still checked
+	a.Lock()
+	b.RLock()
+	b.RUnlock()
+	a.Unlock()
+	b.Lock()
+	assert.Panics(t, func() {
+		a.RLock()
+	})
+	b.Unlock()
+	require.Len(t, *reports, 1)
+	assert.Equal(t, gsync.LockOrderInversion, (*reports)[0].Kind)
+}
+
+func TestDeadlock_DefaultReporterPanics(t *testing.T) {
+	m := gsync.NewMutex(0)
+	m.Lock()
+	assert.Panics(t, func() {
+		m.Lock()
+	})
+	m.Unlock()
+}
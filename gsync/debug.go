@@ -0,0 +1,6 @@
+//go:build gsync_debug
+
+package gsync
+
+// debug enables runtime checks of guards usage
+const debugGuards = true
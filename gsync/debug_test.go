@@ -0,0 +1,36 @@
+//go:build gsync_debug
+
+package gsync_test
+
+import (
+	"gtools/gsync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard_UseAfterUnlock(t *testing.T) {
+	m := gsync.NewMutex(1)
+	g := m.LockGuard()
+	g.Unlock()
+	assert.Panics(t, func() {
+		g.Get()
+	})
+	assert.Panics(t, func() {
+		g.Set(2)
+	})
+	assert.Panics(t, func() {
+		g.With(func(*int) {})
+	})
+	assert.Panics(t, func() {
+		g.Unlock()
+	})
+	assert.Equal(t, 1, m.Get())
+
+	rw := gsync.NewRWMutex(1)
+	rg := rw.RLockGuard()
+	rg.Unlock()
+	assert.Panics(t, func() {
+		rg.Get()
+	})
+}
@@ -0,0 +1,186 @@
+package gsync
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"gtools/ft"
+	"gtools/result"
+)
+
+// Future is a result of func running asynchronously (see Async)
+type Future[T any] struct {
+	done chan struct{}
+	v    T
+	err  error
+	// mu guards waiters: channels notified on completion (see completion)
+	mu       sync.Mutex
+	finished bool
+	waiters  list.List
+}
+
+// futureWaiter receives index of completed future
+type futureWaiter struct {
+	ch  chan<- int
+	idx int
+}
+
+// Async runs `f` in new goroutine and returns Future of its result
+// panic in `f` is recovered and converted into *PanicError
+func Async[T any](f func() (T, error)) *Future[T] {
+	fut := &Future[T]{
+		done: make(chan struct{}),
+	}
+	go func() {
+		fut.v, fut.err = callRecover(f)
+		fut.complete()
+	}()
+	return fut
+}
+
+func (f *Future[T]) complete() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.finished = true
+	close(f.done)
+	for e := f.waiters.Front(); e != nil; e = e.Next() {
+		w := e.Value.(futureWaiter)
+		// channel has room for every future it waits for
+		w.ch <- w.idx
+	}
+	f.waiters.Init()
+}
+
+// notify sends `idx` into `ch` when future is completed
+// returned func removes subscription if it is not needed anymore
+func (f *Future[T]) notify(ch chan<- int, idx int) (cancel func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.finished {
+		ch <- idx
+		return func() {}
+	}
+	e := f.waiters.PushBack(futureWaiter{ch: ch, idx: idx})
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.finished {
+			f.waiters.Remove(e)
+		}
+	}
+}
+
+// Done returns channel that is closed when result is ready
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await waits for result or until `ctx` is done (then ctx.Err() is returned)
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.v, f.err
+	case <-ctx.Done():
+		var t T
+		return t, ctx.Err()
+	}
+}
+
+// Result waits for result and returns it as result.Result
+func (f *Future[T]) Result() result.Result[T] {
+	<-f.done
+	return result.Of(f.v, f.err)
+}
+
+// Then returns Future of `next` applied to result of `f`
+// if `f` fails `next` is not called and returned Future fails with the same error
+func Then[T any, K any](f *Future[T], next func(T) (K, error)) *Future[K] {
+	return Async(func() (K, error) {
+		<-f.done
+		if f.err != nil {
+			var k K
+			return k, f.err
+		}
+		return next(f.v)
+	})
+}
+
+// completion returns channel that receives indexes of futures in order of their completion
+// it does not start goroutines, returned func unsubscribes from futures that are not completed yet
+func completion[T any](futures []*Future[T]) (<-chan int, func()) {
+	ch := make(chan int, len(futures))
+	cancels := make([]func(), len(futures))
+	for i, f := range futures {
+		cancels[i] = f.notify(ch, i)
+	}
+	return ch, func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
+
+// AwaitAll waits for all futures and returns their results in order of `futures`
+// returns immediately when any future fails or `ctx` is done
+func AwaitAll[T any](ctx context.Context, futures ...*Future[T]) ([]T, error) {
+	done, cancel := completion(futures)
+	defer cancel()
+	for range futures {
+		select {
+		case i := <-done:
+			if err := futures[i].err; err != nil {
+				return nil, err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	res := make([]T, len(futures))
+	for i, f := range futures {
+		res[i] = f.v
+	}
+	return res, nil
+}
+
+// AwaitAny waits for first completed future and returns its index and result
+// if `ctx` is done (or there are no futures) index is -1
+func AwaitAny[T any](ctx context.Context, futures ...*Future[T]) (int, T, error) {
+	var t T
+	if len(futures) == 0 {
+		return -1, t, nil
+	}
+	done, cancel := completion(futures)
+	defer cancel()
+	select {
+	case i := <-done:
+		return i, futures[i].v, futures[i].err
+	case <-ctx.Done():
+		return -1, t, ctx.Err()
+	}
+}
+
+type completedIter[T any] struct {
+	futures []*Future[T]
+	done    <-chan int
+	left    int
+}
+
+func (ci *completedIter[T]) Next() (result.Result[T], bool) {
+	if ci.left == 0 {
+		return result.Result[T]{}, false
+	}
+	ci.left--
+	return ci.futures[<-ci.done].Result(), true
+}
+
+// Completed returns iterator over results of futures in order of their completion
+// Next blocks until next future is completed
+func Completed[T any](futures ...*Future[T]) ft.Iter[result.Result[T]] {
+	done, _ := completion(futures)
+	return &completedIter[T]{
+		futures: futures,
+		done:    done,
+		left:    len(futures),
+	}
+}
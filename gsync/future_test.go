@@ -0,0 +1,134 @@
+package gsync_test
+
+import (
+	"context"
+	"errors"
+	"gtools/ft"
+	"gtools/gsync"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// after returns Future that resolves to `v` after `d`
+func after[T any](d time.Duration, v T, err error) *gsync.Future[T] {
+	return gsync.Async(func() (T, error) {
+		time.Sleep(d)
+		return v, err
+	})
+}
+
+func TestFuture_Await(t *testing.T) {
+	ctx := context.Background()
+	f := after(5*time.Millisecond, 42, nil)
+	v, err := f.Await(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+	<-f.Done()
+	assert.Equal(t, 42, f.Result().Unwrap())
+
+	timeout, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	_, err = after(time.Second, 1, nil).Await(timeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = gsync.Async(func() (int, error) {
+		panic("boom")
+	}).Await(ctx)
+	var pe *gsync.PanicError
+	assert.ErrorAs(t, err, &pe)
+}
+
+func TestFuture_Then(t *testing.T) {
+	ctx := context.Background()
+	s := gsync.Then(after(time.Millisecond, 42, nil), func(i int) (string, error) {
+		return strconv.Itoa(i), nil
+	})
+	v, err := s.Await(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", v)
+
+	errBoom := errors.New("boom")
+	called := false
+	s = gsync.Then(after(time.Millisecond, 0, errBoom), func(i int) (string, error) {
+		called = true
+		return "", nil
+	})
+	_, err = s.Await(ctx)
+	assert.ErrorIs(t, err, errBoom)
+	assert.False(t, called)
+}
+
+func TestAwaitAll(t *testing.T) {
+	ctx := context.Background()
+	res, err := gsync.AwaitAll(ctx,
+		after(10*time.Millisecond, 1, nil),
+		after(time.Millisecond, 2, nil),
+		after(5*time.Millisecond, 3, nil),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, res)
+
+	errBoom := errors.New("boom")
+	start := time.Now()
+	_, err = gsync.AwaitAll(ctx, after(time.Second, 1, nil), after(time.Millisecond, 0, errBoom))
+	assert.ErrorIs(t, err, errBoom)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestAwaitAny(t *testing.T) {
+	ctx := context.Background()
+	i, v, err := gsync.AwaitAny(ctx,
+		after(time.Second, "slow", nil),
+		after(time.Millisecond, "fast", nil),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	assert.Equal(t, "fast", v)
+
+	i, _, err = gsync.AwaitAny[int](ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, i)
+
+	timeout, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	i, _, err = gsync.AwaitAny(timeout, after(time.Second, 1, nil))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, -1, i)
+}
+
+func TestCompleted(t *testing.T) {
+	errBoom := errors.New("boom")
+	iter := ft.Try(gsync.Completed(
+		after(30*time.Millisecond, 3, nil),
+		after(time.Millisecond, 1, nil),
+		after(15*time.Millisecond, 0, errBoom),
+	), ft.SkipErrors)
+	assert.Equal(t, []int{1, 3}, ft.Collect[int](iter))
+	assert.ErrorIs(t, iter.Err(), errBoom)
+}
+
+func TestAwait_NoGoroutineLeak(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	pending := make([]*gsync.Future[int], 10)
+	for i := range pending {
+		pending[i] = gsync.Async(func() (int, error) {
+			<-block
+			return 0, nil
+		})
+	}
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Microsecond)
+		_, err := gsync.AwaitAll(ctx, pending...)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		_, _, err = gsync.AwaitAny(ctx, pending...)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		cancel()
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before+2)
+}
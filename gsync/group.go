@@ -0,0 +1,114 @@
+package gsync
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is an error created from panic recovered in goroutine of Group
+type PanicError struct {
+	Value any
+	// Stack is a stack trace of panicked goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("gsync: recovered panic: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Group runs funcs in goroutines and collects their results
+// zero Group is ready to use, it has no limit and does not cancel anything on error
+type Group[T any] struct {
+	wg      sync.WaitGroup
+	cancel  context.CancelCauseFunc
+	sem     chan struct{}
+	mu      sync.Mutex
+	results []T
+	err     error
+}
+
+// NewGroup returns new Group and context derived from `ctx`
+// context is cancelled when any func returns error (context.Cause returns this error) or Wait returns
+func NewGroup[T any](ctx context.Context) (*Group[T], context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group[T]{
+		cancel: cancel,
+	}, ctx
+}
+
+// SetLimit limits number of active goroutines, negative `n` means no limit
+// Go blocks until number of active goroutines is less than limit
+// it must not be called while there are active goroutines
+func (g *Group[T]) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("gsync: modify limit while %d goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs `f` in new goroutine, its result is placed into Wait result in order of Go calls
+// panic in `f` is recovered and converted into *PanicError
+func (g *Group[T]) Go(f func() (T, error)) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.mu.Lock()
+	idx := len(g.results)
+	var zero T
+	g.results = append(g.results, zero)
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		v, err := callRecover(f)
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.results[idx] = v
+		if err != nil && g.err == nil {
+			g.err = err
+			if g.cancel != nil {
+				g.cancel(err)
+			}
+		}
+	}()
+}
+
+func callRecover[T any](f func() (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+	return f()
+}
+
+// Wait waits for all goroutines and returns their results in order of Go calls
+// and first returned error (results of failed funcs are also included)
+func (g *Group[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.results, g.err
+}
@@ -0,0 +1,88 @@
+package gsync_test
+
+import (
+	"context"
+	"errors"
+	"gtools/gsync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup_Order(t *testing.T) {
+	var g gsync.Group[int]
+	for i := 0; i < 10; i++ {
+		i := i
+		g.Go(func() (int, error) {
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			return i * i, nil
+		})
+	}
+	res, err := g.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}, res)
+}
+
+func TestGroup_Cancel(t *testing.T) {
+	errBoom := errors.New("boom")
+	g, ctx := gsync.NewGroup[string](context.Background())
+	g.Go(func() (string, error) {
+		<-ctx.Done()
+		return "cancelled", ctx.Err()
+	})
+	g.Go(func() (string, error) {
+		return "", errBoom
+	})
+	res, err := g.Wait()
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, []string{"cancelled", ""}, res)
+	assert.ErrorIs(t, context.Cause(ctx), errBoom)
+
+	g, ctx = gsync.NewGroup[string](context.Background())
+	g.Go(func() (string, error) {
+		return "ok", nil
+	})
+	res, err = g.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ok"}, res)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestGroup_Limit(t *testing.T) {
+	var g gsync.Group[int]
+	g.SetLimit(2)
+	var active, maxActive atomic.Int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() (int, error) {
+			n := active.Add(1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			active.Add(-1)
+			return 1, nil
+		})
+	}
+	res, err := g.Wait()
+	assert.NoError(t, err)
+	assert.Len(t, res, 10)
+	assert.LessOrEqual(t, maxActive.Load(), int32(2))
+}
+
+func TestGroup_Panic(t *testing.T) {
+	errBoom := errors.New("boom")
+	var g gsync.Group[int]
+	g.Go(func() (int, error) {
+		panic(errBoom)
+	})
+	_, err := g.Wait()
+	var pe *gsync.PanicError
+	assert.ErrorAs(t, err, &pe)
+	assert.ErrorIs(t, err, errBoom)
+	assert.Contains(t, string(pe.Stack), "group_test.go")
+}
@@ -0,0 +1,65 @@
+package gsync
+
+// Guard gives access to value protected by locked mutex
+// unlike pointer returned by Lock it can be invalidated:
+// when built with `gsync_debug` tag any use of Guard after Unlock panics
+type Guard[T any] struct {
+	val      *T
+	unlock   func()
+	released bool
+}
+
+func newGuard[T any](val *T, unlock func()) *Guard[T] {
+	return &Guard[T]{
+		val:    val,
+		unlock: unlock,
+	}
+}
+
+func (g *Guard[T]) check() {
+	if debugGuards && g.released {
+		panic("gsync: guard used after unlock")
+	}
+}
+
+// Get returns copy of protected value
+func (g *Guard[T]) Get() T {
+	g.check()
+	return *g.val
+}
+
+// Set replaces protected value with `t`
+func (g *Guard[T]) Set(t T) {
+	g.check()
+	*g.val = t
+}
+
+// With calls `f` with pointer to protected value
+// pointer must not be retained after `f` returns
+func (g *Guard[T]) With(f func(*T)) {
+	g.check()
+	f(g.val)
+}
+
+// Unlock unlocks mutex, guard can not be used after it
+func (g *Guard[T]) Unlock() {
+	g.check()
+	g.released = true
+	g.unlock()
+}
+
+// RGuard gives read access to value protected by read locked RWMutex
+// when built with `gsync_debug` tag any use of RGuard after Unlock panics
+type RGuard[T any] struct {
+	guard *Guard[T]
+}
+
+// Get returns copy of protected value
+func (g *RGuard[T]) Get() T {
+	return g.guard.Get()
+}
+
+// Unlock read unlocks mutex, guard can not be used after it
+func (g *RGuard[T]) Unlock() {
+	g.guard.Unlock()
+}
@@ -0,0 +1,42 @@
+package gsync_test
+
+import (
+	"gtools/gsync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard(t *testing.T) {
+	m := gsync.NewMutex(1)
+	g := m.LockGuard()
+	_, ok := m.TryLock()
+	assert.False(t, ok)
+	assert.Equal(t, 1, g.Get())
+	g.Set(2)
+	g.With(func(v *int) {
+		*v++
+	})
+	g.Unlock()
+	assert.Equal(t, 3, m.Get())
+}
+
+func TestRWGuard(t *testing.T) {
+	m := gsync.NewRWMutex(1)
+	g := m.LockGuard()
+	g.Set(2)
+	g.Unlock()
+
+	r1 := m.RLockGuard()
+	r2 := m.RLockGuard()
+	assert.Equal(t, 2, r1.Get())
+	assert.Equal(t, 2, r2.Get())
+	_, ok := m.TryLock()
+	assert.False(t, ok)
+	r1.Unlock()
+	r2.Unlock()
+
+	_, ok = m.TryLock()
+	assert.True(t, ok)
+	m.Unlock()
+}
@@ -0,0 +1,113 @@
+package gsync
+
+import "sync"
+
+// KeyedMutex is a set of readers-writer locks, one per key
+// entries are reference counted and removed when no goroutine holds or waits for the key,
+// so memory does not grow with number of ever used keys
+// zero KeyedMutex is ready to use
+type KeyedMutex[K comparable] struct {
+	mu      sync.Mutex
+	entries map[K]*keyedEntry
+}
+
+type keyedEntry struct {
+	sync.RWMutex
+	// refs is number of goroutines holding or waiting for the entry
+	refs int
+}
+
+// NewKeyedMutex returns new KeyedMutex
+func NewKeyedMutex[K comparable]() *KeyedMutex[K] {
+	return &KeyedMutex[K]{
+		entries: make(map[K]*keyedEntry),
+	}
+}
+
+func (km *KeyedMutex[K]) acquire(k K) *keyedEntry {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if km.entries == nil {
+		km.entries = make(map[K]*keyedEntry)
+	}
+	e, ok := km.entries[k]
+	if !ok {
+		e = &keyedEntry{}
+		km.entries[k] = e
+	}
+	e.refs++
+	return e
+}
+
+func (km *KeyedMutex[K]) release(k K) *keyedEntry {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	e, ok := km.entries[k]
+	if !ok {
+		panic("gsync: unlock of unlocked key")
+	}
+	e.refs--
+	if e.refs == 0 {
+		delete(km.entries, k)
+	}
+	return e
+}
+
+// Lock locks key `k`
+func (km *KeyedMutex[K]) Lock(k K) {
+	km.acquire(k).Lock()
+}
+
+// Unlock unlocks key `k`
+func (km *KeyedMutex[K]) Unlock(k K) {
+	km.release(k).Unlock()
+}
+
+// TryLock tries to lock key `k` and returns true on success
+func (km *KeyedMutex[K]) TryLock(k K) bool {
+	if km.acquire(k).TryLock() {
+		return true
+	}
+	km.release(k)
+	return false
+}
+
+// RLock read locks key `k`
+func (km *KeyedMutex[K]) RLock(k K) {
+	km.acquire(k).RLock()
+}
+
+// RUnlock read unlocks key `k`
+func (km *KeyedMutex[K]) RUnlock(k K) {
+	km.release(k).RUnlock()
+}
+
+// TryRLock tries to read lock key `k` and returns true on success
+func (km *KeyedMutex[K]) TryRLock(k K) bool {
+	if km.acquire(k).TryRLock() {
+		return true
+	}
+	km.release(k)
+	return false
+}
+
+// WithKey calls `f` while key `k` is locked
+func (km *KeyedMutex[K]) WithKey(k K, f func()) {
+	km.Lock(k)
+	defer km.Unlock(k)
+	f()
+}
+
+// WithRKey calls `f` while key `k` is read locked
+func (km *KeyedMutex[K]) WithRKey(k K, f func()) {
+	km.RLock(k)
+	defer km.RUnlock(k)
+	f()
+}
+
+// Len returns number of keys that are locked or waited for
+func (km *KeyedMutex[K]) Len() int {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	return len(km.entries)
+}
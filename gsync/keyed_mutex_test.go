@@ -0,0 +1,126 @@
+package gsync_test
+
+import (
+	"gtools/gsync"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex_Simple(t *testing.T) {
+	km := gsync.NewKeyedMutex[string]()
+	km.Lock("a")
+	assert.False(t, km.TryLock("a"))
+	assert.False(t, km.TryRLock("a"))
+	// other keys are not blocked
+	assert.True(t, km.TryLock("b"))
+	assert.Equal(t, 2, km.Len())
+	km.Unlock("b")
+	km.Unlock("a")
+	assert.Equal(t, 0, km.Len())
+
+	km.RLock("a")
+	assert.True(t, km.TryRLock("a"))
+	assert.False(t, km.TryLock("a"))
+	km.RUnlock("a")
+	km.RUnlock("a")
+	assert.Equal(t, 0, km.Len())
+
+	assert.Panics(t, func() {
+		km.Unlock("c")
+	})
+}
+
+func TestKeyedMutex_Zero(t *testing.T) {
+	var km gsync.KeyedMutex[int]
+	called := false
+	km.WithKey(1, func() {
+		called = true
+		assert.False(t, km.TryLock(1))
+	})
+	km.WithRKey(1, func() {
+		assert.True(t, km.TryRLock(1))
+		km.RUnlock(1)
+	})
+	assert.True(t, called)
+	assert.Equal(t, 0, km.Len())
+}
+
+func TestKeyedMutex_Concurrent(t *testing.T) {
+	const goroutines, iterations, keys = 8, 300, 5
+	km := gsync.NewKeyedMutex[int]()
+	counters := make([]int, keys)
+	wg := sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				k := (i + j) % keys
+				km.WithKey(k, func() {
+					counters[k]++
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	total := 0
+	for _, c := range counters {
+		total += c
+	}
+	assert.Equal(t, goroutines*iterations, total)
+	assert.Equal(t, 0, km.Len())
+}
+
+func TestKeyedMutex_Waiter(t *testing.T) {
+	km := gsync.NewKeyedMutex[string]()
+	km.Lock("a")
+	locked := make(chan struct{})
+	go func() {
+		km.Lock("a")
+		close(locked)
+		km.Unlock("a")
+	}()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-locked:
+		t.Fatal("key is locked twice")
+	default:
+	}
+	km.Unlock("a")
+	<-locked
+	assert.Equal(t, 0, km.Len())
+}
+
+func BenchmarkKeyedMutex_HighCardinality(b *testing.B) {
+	km := gsync.NewKeyedMutex[string]()
+	keys := make([]string, 100000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			k := keys[i%len(keys)]
+			km.Lock(k)
+			km.Unlock(k)
+			i += 7
+		}
+	})
+}
+
+func BenchmarkKeyedMutex_LowCardinality(b *testing.B) {
+	km := gsync.NewKeyedMutex[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			km.Lock(i % 4)
+			km.Unlock(i % 4)
+			i++
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package gsync
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Lazy is a value computed on first use
+//
+//	var config = gsync.NewLazy(loadConfig)
+//	...
+//	timeout := config.Get().Timeout
+type Lazy[T any] struct {
+	once     sync.Once
+	f        func() T
+	v        T
+	panicked any
+}
+
+// NewLazy returns Lazy which value is computed by `f` on first Get
+func NewLazy[T any](f func() T) *Lazy[T] {
+	return &Lazy[T]{
+		f: f,
+	}
+}
+
+// Get returns value computing it on first call, concurrent calls wait for computation
+// if `f` panics, Get panics with the same value on every call
+func (l *Lazy[T]) Get() T {
+	l.once.Do(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				l.panicked = r
+				panic(r)
+			}
+		}()
+		l.v = l.f()
+		l.f = nil
+	})
+	if l.panicked != nil {
+		panic(l.panicked)
+	}
+	return l.v
+}
+
+// LazyErr is a value computed on first use by func that may fail
+type LazyErr[T any] struct {
+	mu    sync.Mutex
+	done  atomic.Bool
+	f     func() (T, error)
+	retry bool
+	v     T
+	err   error
+}
+
+// NewLazyErr returns LazyErr which value is computed by `f` on first Get
+// by default error is cached same as value, if optional argument `retryOnError` is true
+// error is not cached and next Get calls `f` again
+func NewLazyErr[T any](f func() (T, error), retryOnError ...bool) *LazyErr[T] {
+	l := &LazyErr[T]{
+		f: f,
+	}
+	if len(retryOnError) > 0 {
+		l.retry = retryOnError[0]
+	}
+	return l
+}
+
+// Get returns value and error of `f` computing them on first call, concurrent calls wait for computation
+func (l *LazyErr[T]) Get() (T, error) {
+	if l.done.Load() {
+		return l.v, l.err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done.Load() {
+		return l.v, l.err
+	}
+	v, err := l.f()
+	if err != nil && l.retry {
+		return v, err
+	}
+	l.v, l.err = v, err
+	l.f = nil
+	l.done.Store(true)
+	return l.v, l.err
+}
+
+// OnceValue is a lazy value that can be invalidated by Reset (e.g. on config reload)
+// and computed again on next Get
+type OnceValue[T any] struct {
+	mu sync.Mutex
+	f  func() T
+	v  atomic.Pointer[T]
+}
+
+// NewOnceValue returns OnceValue which value is computed by `f`
+func NewOnceValue[T any](f func() T) *OnceValue[T] {
+	return &OnceValue[T]{
+		f: f,
+	}
+}
+
+// Get returns value computing it if it is first call or value was reset
+// concurrent calls wait for computation
+func (o *OnceValue[T]) Get() T {
+	if v := o.v.Load(); v != nil {
+		return *v
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if v := o.v.Load(); v != nil {
+		return *v
+	}
+	v := o.f()
+	o.v.Store(&v)
+	return v
+}
+
+// Reset invalidates value, so next Get computes it again
+// if value is being computed Reset waits for computation to finish and invalidates its result
+func (o *OnceValue[T]) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.v.Store(nil)
+}
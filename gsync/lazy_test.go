@@ -0,0 +1,98 @@
+package gsync_test
+
+import (
+	"errors"
+	"gtools/gsync"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrently calls `f` from several goroutines
+func concurrently(n int, f func()) {
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLazy(t *testing.T) {
+	var calls atomic.Int32
+	l := gsync.NewLazy(func() []int {
+		calls.Add(1)
+		return []int{1, 2}
+	})
+	concurrently(8, func() {
+		assert.Equal(t, []int{1, 2}, l.Get())
+	})
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLazy_Panic(t *testing.T) {
+	calls := 0
+	l := gsync.NewLazy(func() int {
+		calls++
+		panic("boom")
+	})
+	assert.PanicsWithValue(t, "boom", func() { l.Get() })
+	assert.PanicsWithValue(t, "boom", func() { l.Get() })
+	assert.Equal(t, 1, calls)
+}
+
+func TestLazyErr(t *testing.T) {
+	errBoom := errors.New("boom")
+	var calls atomic.Int32
+	l := gsync.NewLazyErr(func() (int, error) {
+		calls.Add(1)
+		return 0, errBoom
+	})
+	concurrently(8, func() {
+		_, err := l.Get()
+		assert.ErrorIs(t, err, errBoom)
+	})
+	assert.Equal(t, int32(1), calls.Load())
+
+	calls.Store(0)
+	l = gsync.NewLazyErr(func() (int, error) {
+		if calls.Add(1) < 3 {
+			return 0, errBoom
+		}
+		return 42, nil
+	}, true)
+	_, err := l.Get()
+	assert.ErrorIs(t, err, errBoom)
+	_, err = l.Get()
+	assert.ErrorIs(t, err, errBoom)
+	concurrently(8, func() {
+		v, err := l.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, 42, v)
+	})
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestOnceValue(t *testing.T) {
+	var version atomic.Int32
+	o := gsync.NewOnceValue(func() int32 {
+		return version.Add(1)
+	})
+	concurrently(8, func() {
+		assert.Equal(t, int32(1), o.Get())
+	})
+	o.Reset()
+	assert.Equal(t, int32(2), o.Get())
+	assert.Equal(t, int32(2), o.Get())
+
+	concurrently(8, func() {
+		o.Reset()
+		o.Get()
+	})
+	assert.Equal(t, version.Load(), o.Get())
+}
@@ -0,0 +1,283 @@
+package gsync
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// lastLockID is used to assign unique ids to locks
+var lastLockID atomic.Uint64
+
+const (
+	// lockWriter is set while exclusive lock is held
+	lockWriter uint64 = 1 << iota
+	// lockWaiters is set while there are waiters in the queue, it sends lockers to slow path
+	lockWaiters
+	// lockReader is a unit of readers counter stored in the rest bits of state
+	// (state.Add(^(lockReader - 1)) decrements it)
+	lockReader
+)
+
+// rwLock is a readers-writer lock which acquisition can be cancelled by context
+//
+// uncontended lock and unlock are single atomic operations on `state`.
+// Contended lockers wait in FIFO queue guarded by `mu` and lock is handed over to them directly,
+// so unlock wakes only waiters that can proceed. Waiters block new readers, so writers are not starved
+type rwLock struct {
+	id    uint64
+	state atomic.Uint64
+	// mu guards waiters
+	mu      sync.Mutex
+	waiters list.List
+	// inst is nil if mutex is not instrumented
+	inst *instrumentation
+	// tracker is nil if deadlock detection is disabled
+	tracker *lockTracker
+}
+
+type lockWaiter struct {
+	writer bool
+	// ready is closed when lock is handed over to the waiter
+	ready   chan struct{}
+	granted bool
+}
+
+func newRWLock(opts []Option) *rwLock {
+	id := lastLockID.Add(1)
+	return &rwLock{
+		id:      id,
+		inst:    newInstrumentation(opts),
+		tracker: newLockTracker(id),
+	}
+}
+
+// start returns time when lock acquisition started (only if lock is instrumented)
+func (l *rwLock) start() time.Time {
+	if l.inst == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (l *rwLock) acquired(kind LockKind, start time.Time) {
+	if l.inst != nil {
+		l.inst.acquired(kind, start)
+	}
+}
+
+// grant hands lock over to waiters from the head of the queue while it is possible. Must be called with mu held
+func (l *rwLock) grant() {
+	for e := l.waiters.Front(); e != nil; e = l.waiters.Front() {
+		w := e.Value.(*lockWaiter)
+		if !l.tryAcquire(w.writer) {
+			break
+		}
+		l.waiters.Remove(e)
+		w.granted = true
+		close(w.ready)
+		if w.writer {
+			break
+		}
+	}
+	if l.waiters.Len() == 0 {
+		l.state.And(^lockWaiters)
+	}
+}
+
+// tryAcquire acquires lock ignoring waiters flag
+func (l *rwLock) tryAcquire(writer bool) bool {
+	for {
+		s := l.state.Load()
+		if s&lockWriter != 0 || (writer && s >= lockReader) {
+			return false
+		}
+		next := s + lockReader
+		if writer {
+			next = s | lockWriter
+		}
+		if l.state.CompareAndSwap(s, next) {
+			return true
+		}
+	}
+}
+
+// wait acquires lock in slow path, returns false if `done` is closed before lock is acquired
+func (l *rwLock) wait(writer bool, done <-chan struct{}) bool {
+	l.mu.Lock()
+	if l.waiters.Len() == 0 && l.tryAcquire(writer) {
+		l.mu.Unlock()
+		return true
+	}
+	w := &lockWaiter{
+		writer: writer,
+		ready:  make(chan struct{}),
+	}
+	e := l.waiters.PushBack(w)
+	l.state.Or(lockWaiters)
+	// lock may be released before waiters flag was set
+	l.grant()
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return true
+	case <-done:
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w.granted {
+		// lock was handed over concurrently with cancellation, give it back
+		if writer {
+			l.state.And(^lockWriter)
+		} else {
+			l.state.Add(^(lockReader - 1))
+		}
+	} else {
+		l.waiters.Remove(e)
+	}
+	// waiters blocked by this one may continue
+	l.grant()
+	return false
+}
+
+// plain reports whether lock is neither instrumented nor tracked by deadlock detector,
+// such lock can be acquired and released by single atomic operation
+func (l *rwLock) plain() bool {
+	return !DeadlockDetection && l.inst == nil
+}
+
+func (l *rwLock) lock() {
+	if l.plain() && l.state.CompareAndSwap(0, lockWriter) {
+		return
+	}
+	_ = l.lockCtx(context.Background())
+}
+
+func (l *rwLock) tryLock() bool {
+	start := l.start()
+	if !l.state.CompareAndSwap(0, lockWriter) {
+		return false
+	}
+	l.acquired(Exclusive, start)
+	detectLocked(l.id, Exclusive)
+	return true
+}
+
+func (l *rwLock) lockCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := ctx.Done()
+	if done == nil {
+		// only acquisitions that can wait forever may deadlock
+		detectBeforeLock(l.id, Exclusive)
+	}
+	start := l.start()
+	if !l.state.CompareAndSwap(0, lockWriter) && !l.wait(true, done) {
+		return ctx.Err()
+	}
+	l.acquired(Exclusive, start)
+	detectLocked(l.id, Exclusive)
+	return nil
+}
+
+func (l *rwLock) unlock() {
+	if l.plain() && l.state.CompareAndSwap(lockWriter, 0) {
+		return
+	}
+	s := l.state.Load()
+	if s&lockWriter == 0 {
+		panic("gsync: unlock of unlocked mutex")
+	}
+	var report func()
+	if l.inst != nil {
+		report = l.inst.released()
+	}
+	if !l.state.CompareAndSwap(lockWriter, 0) {
+		l.mu.Lock()
+		l.state.And(^lockWriter)
+		l.grant()
+		l.mu.Unlock()
+	}
+	detectUnlocked(l.id)
+	if report != nil {
+		report()
+	}
+}
+
+func (l *rwLock) rlock() {
+	if l.plain() && l.tryRLockFast() {
+		return
+	}
+	_ = l.rlockCtx(context.Background())
+}
+
+// tryRLockFast acquires read lock if there is no writer and no waiters
+func (l *rwLock) tryRLockFast() bool {
+	if l.state.Add(lockReader)&(lockWriter|lockWaiters) == 0 {
+		return true
+	}
+	// optimistic acquisition failed, it may have prevented handing lock over to waiters
+	l.releaseReader()
+	return false
+}
+
+// releaseReader decrements readers counter and hands lock over to waiters if it was the last reader
+func (l *rwLock) releaseReader() uint64 {
+	s := l.state.Add(^(lockReader - 1))
+	if s == lockWaiters {
+		l.mu.Lock()
+		l.grant()
+		l.mu.Unlock()
+	}
+	return s
+}
+
+func (l *rwLock) tryRLock() bool {
+	start := l.start()
+	if !l.tryRLockFast() {
+		return false
+	}
+	l.sharedAcquired(start)
+	return true
+}
+
+func (l *rwLock) rlockCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := ctx.Done()
+	if done == nil {
+		// only acquisitions that can wait forever may deadlock
+		detectBeforeLock(l.id, Shared)
+	}
+	start := l.start()
+	if !l.tryRLockFast() && !l.wait(false, done) {
+		return ctx.Err()
+	}
+	l.sharedAcquired(start)
+	return nil
+}
+
+func (l *rwLock) sharedAcquired(start time.Time) {
+	if l.inst != nil {
+		l.inst.sharedAcquired()
+	}
+	l.acquired(Shared, start)
+	detectLocked(l.id, Shared)
+}
+
+func (l *rwLock) runlock() {
+	if s := l.releaseReader(); s+lockReader < lockReader {
+		// counter was decremented from zero
+		l.state.Add(lockReader)
+		panic("gsync: runlock of unlocked mutex")
+	}
+	detectUnlocked(l.id)
+	if l.inst != nil {
+		l.inst.sharedReleased()()
+	}
+}
@@ -0,0 +1,147 @@
+package gsync
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"time"
+)
+
+const (
+	// lockAllSpins is a number of retries of LockAllCtx that only yield processor before sleeping
+	lockAllSpins = 4
+	// lockAllMaxBackoff bounds sleep between retries of LockAllCtx
+	lockAllMaxBackoff = time.Millisecond
+)
+
+// Locker is implemented by Mutex and RWMutex (exclusive lock is used)
+// it is used by LockAll and TryLockAll to lock mutexes protecting values of different types
+type Locker interface {
+	rwLock() *rwLock
+}
+
+func (m *Mutex[T]) rwLock() *rwLock {
+	return m.mutex
+}
+
+func (m *RWMutex[T]) rwLock() *rwLock {
+	return m.mutex
+}
+
+// sortedLocks returns unique locks sorted by id
+func sortedLocks(lockers []Locker) []*rwLock {
+	locks := make([]*rwLock, 0, len(lockers))
+	seen := make(map[uint64]bool, len(lockers))
+	for _, l := range lockers {
+		lock := l.rwLock()
+		if seen[lock.id] {
+			continue
+		}
+		seen[lock.id] = true
+		locks = append(locks, lock)
+	}
+	sort.Slice(locks, func(i, j int) bool {
+		return locks[i].id < locks[j].id
+	})
+	return locks
+}
+
+func unlockAll(locks []*rwLock) func() {
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].unlock()
+		}
+	}
+}
+
+// LockAll locks all provided mutexes and returns func that unlocks them
+// mutexes are always locked in the same global order (regardless of arguments order),
+// so concurrent LockAll calls with same mutexes can not deadlock
+// mutex passed several times is locked once
+//
+//	unlock := gsync.LockAll(from, to)
+//	defer unlock()
+func LockAll(lockers ...Locker) (unlock func()) {
+	locks := sortedLocks(lockers)
+	for _, l := range locks {
+		l.lock()
+	}
+	return unlockAll(locks)
+}
+
+// TryLockAll tries to lock all provided mutexes (in same order as LockAll)
+// if any of them is already locked it unlocks mutexes locked so far and returns nil and false
+// it makes single attempt, use LockAllCtx instead of calling it in a loop
+func TryLockAll(lockers ...Locker) (unlock func(), ok bool) {
+	locks := sortedLocks(lockers)
+	if !tryLockAll(locks) {
+		return nil, false
+	}
+	return unlockAll(locks), true
+}
+
+// tryLockAll locks all `locks` or none of them
+func tryLockAll(locks []*rwLock) bool {
+	for i, l := range locks {
+		if !l.tryLock() {
+			unlockAll(locks[:i])()
+			return false
+		}
+	}
+	return true
+}
+
+// LockAllCtx locks all provided mutexes like TryLockAll, but retries until it succeeds or `ctx` is done
+// unlike LockAll it does not hold any mutex while waiting, so other goroutines may lock them meanwhile
+// retries first yield processor and then sleep with exponential backoff (up to 1ms)
+// on failure returns nil and ctx.Err()
+//
+//	unlock, err := gsync.LockAllCtx(ctx, from, to)
+//	if err != nil {
+//		return err
+//	}
+//	defer unlock()
+func LockAllCtx(ctx context.Context, lockers ...Locker) (unlock func(), err error) {
+	locks := sortedLocks(lockers)
+	var timer *time.Timer
+	backoff := time.Microsecond
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if tryLockAll(locks) {
+			return unlockAll(locks), nil
+		}
+		if attempt < lockAllSpins {
+			runtime.Gosched()
+			continue
+		}
+		if timer == nil {
+			timer = time.NewTimer(backoff)
+			defer timer.Stop()
+		} else {
+			timer.Reset(backoff)
+		}
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if backoff < lockAllMaxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+// Lock2 locks two mutexes in global order (see LockAll)
+// and returns pointers to their values and func that unlocks both mutexes
+// if `a` and `b` are the same mutex it is locked once
+//
+//	from, to, unlock := gsync.Lock2(accounts[i], accounts[j])
+//	defer unlock()
+//	from.Balance -= amount
+//	to.Balance += amount
+func Lock2[A any, B any](a *Mutex[A], b *Mutex[B]) (*A, *B, func()) {
+	unlock := LockAll(a, b)
+	return a.val, b.val, unlock
+}
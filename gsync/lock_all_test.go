@@ -0,0 +1,128 @@
+package gsync_test
+
+import (
+	"context"
+	"gtools/gsync"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type account struct {
+	balance int
+}
+
+func TestLock2_Transfer(t *testing.T) {
+	const goroutines, iterations = 8, 500
+	accounts := []*gsync.Mutex[account]{
+		gsync.NewMutex(account{balance: 1000}),
+		gsync.NewMutex(account{balance: 1000}),
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// half of goroutines transfer in opposite direction
+			from, to := accounts[i%2], accounts[(i+1)%2]
+			for j := 0; j < iterations; j++ {
+				f, t, unlock := gsync.Lock2(from, to)
+				f.balance--
+				t.balance++
+				unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 2000, accounts[0].Get().balance+accounts[1].Get().balance)
+	assert.Equal(t, 1000, accounts[0].Get().balance)
+}
+
+func TestLockAll(t *testing.T) {
+	a := gsync.NewMutex(1)
+	b := gsync.NewRWMutex("b")
+	c := gsync.NewMutex(3.0)
+
+	unlock := gsync.LockAll(c, a, b, a)
+	_, ok := a.TryLock()
+	assert.False(t, ok)
+	_, ok = b.TryRLock()
+	assert.False(t, ok)
+	_, ok = c.TryLock()
+	assert.False(t, ok)
+	unlock()
+
+	unlock, ok = gsync.TryLockAll(a, b, c)
+	assert.True(t, ok)
+	unlock()
+
+	unlock = gsync.LockAll()
+	unlock()
+}
+
+func TestTryLockAll_ReleasesPartial(t *testing.T) {
+	a := gsync.NewMutex(1)
+	b := gsync.NewMutex(2)
+	c := gsync.NewMutex(3)
+	// mutex created last is locked last
+	c.Lock()
+	unlock, ok := gsync.TryLockAll(b, c, a)
+	assert.False(t, ok)
+	assert.Nil(t, unlock)
+	c.Unlock()
+
+	for _, m := range []*gsync.Mutex[int]{a, b, c} {
+		_, ok = m.TryLock()
+		assert.True(t, ok)
+		m.Unlock()
+	}
+}
+
+func TestLockAllCtx(t *testing.T) {
+	a := gsync.NewMutex(1)
+	b := gsync.NewMutex(2)
+	b.Lock()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		b.Unlock()
+	}()
+	unlock, err := gsync.LockAllCtx(context.Background(), a, b)
+	assert.NoError(t, err)
+	_, ok := a.TryLock()
+	assert.False(t, ok)
+	unlock()
+
+	b.Lock()
+	defer b.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	// a is not held while waiting for b
+	held := make(chan bool, 1)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		_, ok := a.TryLock()
+		if ok {
+			a.Unlock()
+		}
+		held <- ok
+	}()
+	unlock, err = gsync.LockAllCtx(ctx, b, a)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, unlock)
+	assert.True(t, <-held)
+	_, ok = a.TryLock()
+	assert.True(t, ok)
+	a.Unlock()
+}
+
+func TestLock2_Same(t *testing.T) {
+	a := gsync.NewMutex(1)
+	x, y, unlock := gsync.Lock2(a, a)
+	assert.Same(t, x, y)
+	unlock()
+	_, ok := a.TryLock()
+	assert.True(t, ok)
+	a.Unlock()
+}
@@ -0,0 +1,243 @@
+package gsync_test
+
+import (
+	"context"
+	"gtools/gsync"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRWMutex_RLockCtx(t *testing.T) {
+	m := gsync.NewRWMutex(1)
+	v, err := m.RLockCtx(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	m.RUnlock()
+
+	val := m.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	v, err = m.RLockCtx(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, v)
+
+	v, ok := m.TryRLockFor(10 * time.Millisecond)
+	assert.False(t, ok)
+	assert.Equal(t, 0, v)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		*val = 2
+		m.Unlock()
+	}()
+	v, ok = m.TryRLockFor(time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	m.RUnlock()
+}
+
+// assertUnlocked checks that no lock is left held
+func assertUnlocked(t *testing.T, lock func() bool, unlock func()) {
+	t.Helper()
+	assert.True(t, lock(), "lock is left held")
+	unlock()
+}
+
+func TestLockCtx_CancelledAcquireDoesNotHoldLock(t *testing.T) {
+	const waiters = 50
+	m := gsync.NewMutex(0)
+	rw := gsync.NewRWMutex(0)
+	m.Lock()
+	rw.Lock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	for i := 0; i < waiters; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, err := m.LockCtx(ctx)
+			assert.ErrorIs(t, err, context.Canceled)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := rw.LockCtx(ctx)
+			assert.ErrorIs(t, err, context.Canceled)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := rw.RLockCtx(ctx)
+			assert.ErrorIs(t, err, context.Canceled)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	wg.Wait()
+	m.Unlock()
+	rw.Unlock()
+
+	assertUnlocked(t, func() bool {
+		_, ok := m.TryLock()
+		return ok
+	}, m.Unlock)
+	assertUnlocked(t, func() bool {
+		_, ok := rw.TryLock()
+		return ok
+	}, rw.Unlock)
+	assertUnlocked(t, func() bool {
+		_, ok := rw.TryRLock()
+		return ok
+	}, rw.RUnlock)
+}
+
+func TestLockCtx_RaceWithUnlock(t *testing.T) {
+	// acquires that race with cancellation either get the lock or return error, but never leak it
+	m := gsync.NewRWMutex(0)
+	for i := 0; i < 200; i++ {
+		m.Lock()
+		ctx, cancel := context.WithCancel(context.Background())
+		results := make(chan error, 2)
+		go func() {
+			_, err := m.LockCtx(ctx)
+			if err == nil {
+				m.Unlock()
+			}
+			results <- err
+		}()
+		go func() {
+			_, err := m.RLockCtx(ctx)
+			if err == nil {
+				m.RUnlock()
+			}
+			results <- err
+		}()
+		go cancel()
+		m.Unlock()
+		<-results
+		<-results
+		cancel()
+		assertUnlocked(t, func() bool {
+			_, ok := m.TryLock()
+			return ok
+		}, m.Unlock)
+	}
+}
+
+func TestRWMutex_Stress(t *testing.T) {
+	m := gsync.NewRWMutex(0)
+	var writers, readers atomic.Int32
+	check := func() {
+		w, r := writers.Load(), readers.Load()
+		if w > 1 || (w == 1 && r > 0) {
+			t.Errorf("mutual exclusion violated: %d writers, %d readers", w, r)
+		}
+	}
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i%3)*time.Microsecond)
+				switch (g + i) % 4 {
+				case 0:
+					v := m.Lock()
+					writers.Add(1)
+					check()
+					*v++
+					writers.Add(-1)
+					m.Unlock()
+				case 1:
+					if v, err := m.LockCtx(ctx); err == nil {
+						writers.Add(1)
+						check()
+						*v++
+						writers.Add(-1)
+						m.Unlock()
+					}
+				case 2:
+					m.RLock()
+					readers.Add(1)
+					check()
+					readers.Add(-1)
+					m.RUnlock()
+				default:
+					if _, err := m.RLockCtx(ctx); err == nil {
+						readers.Add(1)
+						check()
+						readers.Add(-1)
+						m.RUnlock()
+					}
+				}
+				cancel()
+			}
+		}(g)
+	}
+	wg.Wait()
+	// lock is free after all acquisitions
+	_, ok := m.TryLock()
+	assert.True(t, ok)
+	m.Unlock()
+}
+
+// benchmarks use only API available since first version of the package, so they can be compared with it
+
+func BenchmarkMutex_Uncontended(b *testing.B) {
+	m := gsync.NewMutex(0)
+	for i := 0; i < b.N; i++ {
+		v := m.Lock()
+		*v++
+		m.Unlock()
+	}
+}
+
+func BenchmarkMutex_Contended(b *testing.B) {
+	m := gsync.NewMutex(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			v := m.Lock()
+			*v++
+			m.Unlock()
+		}
+	})
+}
+
+func BenchmarkRWMutex_RLockUncontended(b *testing.B) {
+	m := gsync.NewRWMutex(0)
+	for i := 0; i < b.N; i++ {
+		m.RLock()
+		m.RUnlock()
+	}
+}
+
+func BenchmarkRWMutex_RLockParallel(b *testing.B) {
+	m := gsync.NewRWMutex(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.RLock()
+			m.RUnlock()
+		}
+	})
+}
+
+func BenchmarkRWMutex_ReadMostly(b *testing.B) {
+	m := gsync.NewRWMutex(0)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%10 == 0 {
+				v := m.Lock()
+				*v++
+				m.Unlock()
+			} else {
+				m.RLock()
+				m.RUnlock()
+			}
+			i++
+		}
+	})
+}
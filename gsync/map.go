@@ -0,0 +1,265 @@
+package gsync
+
+import (
+	"hash/maphash"
+	"sync"
+
+	"gtools/ft"
+)
+
+// Map is a typed wrapper over sync.Map
+// values are stored boxed, so Compute works for values that are not comparable
+// zero Map is ready to use
+type Map[K comparable, V any] struct {
+	m sync.Map
+}
+
+// Load returns value stored for key `k`, ok is false if there is no value
+func (m *Map[K, V]) Load(k K) (v V, ok bool) {
+	p, ok := m.m.Load(k)
+	if !ok {
+		return v, false
+	}
+	return *p.(*V), true
+}
+
+// Store sets value for key `k`
+func (m *Map[K, V]) Store(k K, v V) {
+	m.m.Store(k, &v)
+}
+
+// LoadOrStore returns existing value for key `k` if present (loaded is true)
+// otherwise it stores and returns `v`
+func (m *Map[K, V]) LoadOrStore(k K, v V) (actual V, loaded bool) {
+	p, loaded := m.m.LoadOrStore(k, &v)
+	return *p.(*V), loaded
+}
+
+// LoadAndDelete deletes value for key `k` returning previous value if any
+func (m *Map[K, V]) LoadAndDelete(k K) (v V, loaded bool) {
+	p, loaded := m.m.LoadAndDelete(k)
+	if !loaded {
+		return v, false
+	}
+	return *p.(*V), true
+}
+
+// Delete deletes value for key `k`
+func (m *Map[K, V]) Delete(k K) {
+	m.m.Delete(k)
+}
+
+// Compute atomically updates value for key `k`
+// `f` receives current value (loaded is false if there is none) and returns new value,
+// if `keep` is false value is deleted
+// `f` may be called several times on concurrent modifications of the same key, so it must not have side effects
+// returns resulting value and true if it is stored
+func (m *Map[K, V]) Compute(k K, f func(old V, loaded bool) (v V, keep bool)) (V, bool) {
+	for {
+		var old V
+		p, loaded := m.m.Load(k)
+		if loaded {
+			old = *p.(*V)
+		}
+		v, keep := f(old, loaded)
+		switch {
+		case !keep && !loaded:
+			var zero V
+			return zero, false
+		case !keep:
+			if m.m.CompareAndDelete(k, p) {
+				var zero V
+				return zero, false
+			}
+		case loaded:
+			if m.m.CompareAndSwap(k, p, &v) {
+				return v, true
+			}
+		default:
+			if _, loaded := m.m.LoadOrStore(k, &v); !loaded {
+				return v, true
+			}
+		}
+	}
+}
+
+// Range calls `f` for each key and value, stops if `f` returns false
+// same as sync.Map.Range it does not correspond to any consistent snapshot
+func (m *Map[K, V]) Range(f func(k K, v V) bool) {
+	m.m.Range(func(k, p any) bool {
+		return f(k.(K), *p.(*V))
+	})
+}
+
+// Iter returns iterator over copy of map pairs
+func (m *Map[K, V]) Iter() ft.Iter[ft.MapPair[K, V]] {
+	var pairs []ft.MapPair[K, V]
+	m.Range(func(k K, v V) bool {
+		pairs = append(pairs, ft.MapPair[K, V]{Key: k, Value: v})
+		return true
+	})
+	return ft.SliceIter(pairs)
+}
+
+// DefaultShards is a number of shards used by NewShardedMap by default
+const DefaultShards = 32
+
+// ShardedMap is a concurrent map split into shards, each of them guarded by its own RWMutex
+// use it instead of Map for write-heavy workloads
+type ShardedMap[K comparable, V any] struct {
+	hash   func(K) uint64
+	shards []mapShard[K, V]
+}
+
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+	// pad prevents false sharing of neighbour shards
+	_ [32]byte
+}
+
+// NewShardedMap returns new ShardedMap
+// `hash` is used to choose shard of key (see HashString and HashInt)
+// optional argument `shards` sets number of shards (DefaultShards by default)
+func NewShardedMap[K comparable, V any](hash func(K) uint64, shards ...int) *ShardedMap[K, V] {
+	n := DefaultShards
+	if len(shards) > 0 && shards[0] > 0 {
+		n = shards[0]
+	}
+	sm := &ShardedMap[K, V]{
+		hash:   hash,
+		shards: make([]mapShard[K, V], n),
+	}
+	for i := range sm.shards {
+		sm.shards[i].m = make(map[K]V)
+	}
+	return sm
+}
+
+func (sm *ShardedMap[K, V]) shard(k K) *mapShard[K, V] {
+	return &sm.shards[sm.hash(k)%uint64(len(sm.shards))]
+}
+
+// Load returns value stored for key `k`, ok is false if there is no value
+func (sm *ShardedMap[K, V]) Load(k K) (v V, ok bool) {
+	s := sm.shard(k)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok = s.m[k]
+	return v, ok
+}
+
+// Store sets value for key `k`
+func (sm *ShardedMap[K, V]) Store(k K, v V) {
+	s := sm.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[k] = v
+}
+
+// LoadOrStore returns existing value for key `k` if present (loaded is true)
+// otherwise it stores and returns `v`
+func (sm *ShardedMap[K, V]) LoadOrStore(k K, v V) (actual V, loaded bool) {
+	s := sm.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if actual, loaded = s.m[k]; loaded {
+		return actual, true
+	}
+	s.m[k] = v
+	return v, false
+}
+
+// LoadAndDelete deletes value for key `k` returning previous value if any
+func (sm *ShardedMap[K, V]) LoadAndDelete(k K) (v V, loaded bool) {
+	s := sm.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, loaded = s.m[k]
+	delete(s.m, k)
+	return v, loaded
+}
+
+// Delete deletes value for key `k`
+func (sm *ShardedMap[K, V]) Delete(k K) {
+	sm.LoadAndDelete(k)
+}
+
+// Compute atomically updates value for key `k`
+// `f` receives current value (loaded is false if there is none) and returns new value,
+// if `keep` is false value is deleted
+// `f` is called once under shard lock, so it must not access the map
+// returns resulting value and true if it is stored
+func (sm *ShardedMap[K, V]) Compute(k K, f func(old V, loaded bool) (v V, keep bool)) (V, bool) {
+	s := sm.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, loaded := s.m[k]
+	v, keep := f(old, loaded)
+	if !keep {
+		delete(s.m, k)
+		var zero V
+		return zero, false
+	}
+	s.m[k] = v
+	return v, true
+}
+
+// Len returns number of stored values
+func (sm *ShardedMap[K, V]) Len() int {
+	n := 0
+	for i := range sm.shards {
+		s := &sm.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range calls `f` for each key and value, stops if `f` returns false
+// each shard is read locked while its values are passed to `f`, so `f` must not modify the map
+func (sm *ShardedMap[K, V]) Range(f func(k K, v V) bool) {
+	for i := range sm.shards {
+		s := &sm.shards[i]
+		s.mu.RLock()
+		for k, v := range s.m {
+			if !f(k, v) {
+				s.mu.RUnlock()
+				return
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
+
+// Iter returns iterator over copy of map pairs
+// every shard is copied under its lock, but shards are copied one by one
+func (sm *ShardedMap[K, V]) Iter() ft.Iter[ft.MapPair[K, V]] {
+	var pairs []ft.MapPair[K, V]
+	sm.Range(func(k K, v V) bool {
+		pairs = append(pairs, ft.MapPair[K, V]{Key: k, Value: v})
+		return true
+	})
+	return ft.SliceIter(pairs)
+}
+
+var hashSeed = maphash.MakeSeed()
+
+// HashString is a hash func of strings that can be used by ShardedMap
+func HashString[S ~string](s S) uint64 {
+	return maphash.String(hashSeed, string(s))
+}
+
+// HashInt is a hash func of integers that can be used by ShardedMap
+func HashInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64 |
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](t T) uint64 {
+	// splitmix64 finalizer spreads sequential keys over shards
+	x := uint64(t)
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
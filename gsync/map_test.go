@@ -0,0 +1,210 @@
+package gsync_test
+
+import (
+	"gtools/ft"
+	"gtools/gsync"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrentMap is implemented by both Map and ShardedMap
+type concurrentMap[K comparable, V any] interface {
+	Load(K) (V, bool)
+	Store(K, V)
+	LoadOrStore(K, V) (V, bool)
+	LoadAndDelete(K) (V, bool)
+	Delete(K)
+	Compute(K, func(V, bool) (V, bool)) (V, bool)
+	Iter() ft.Iter[ft.MapPair[K, V]]
+}
+
+func maps() map[string]concurrentMap[string, []int] {
+	return map[string]concurrentMap[string, []int]{
+		"Map":        &gsync.Map[string, []int]{},
+		"ShardedMap": gsync.NewShardedMap[string, []int](gsync.HashString[string], 4),
+	}
+}
+
+func TestMaps_Simple(t *testing.T) {
+	for name, m := range maps() {
+		t.Run(name, func(t *testing.T) {
+			_, ok := m.Load("a")
+			assert.False(t, ok)
+			m.Store("a", []int{1})
+			v, ok := m.Load("a")
+			assert.True(t, ok)
+			assert.Equal(t, []int{1}, v)
+
+			v, loaded := m.LoadOrStore("a", []int{2})
+			assert.True(t, loaded)
+			assert.Equal(t, []int{1}, v)
+			v, loaded = m.LoadOrStore("b", []int{2})
+			assert.False(t, loaded)
+			assert.Equal(t, []int{2}, v)
+
+			pairs := ft.Collect(m.Iter())
+			sort.Slice(pairs, func(i, j int) bool {
+				return pairs[i].Key < pairs[j].Key
+			})
+			assert.Equal(t, []ft.MapPair[string, []int]{
+				{Key: "a", Value: []int{1}},
+				{Key: "b", Value: []int{2}},
+			}, pairs)
+
+			v, loaded = m.LoadAndDelete("a")
+			assert.True(t, loaded)
+			assert.Equal(t, []int{1}, v)
+			_, loaded = m.LoadAndDelete("a")
+			assert.False(t, loaded)
+			m.Delete("b")
+			assert.Empty(t, ft.Collect(m.Iter()))
+		})
+	}
+}
+
+func TestMaps_Compute(t *testing.T) {
+	appendValue := func(x int) func([]int, bool) ([]int, bool) {
+		return func(old []int, _ bool) ([]int, bool) {
+			return append(append([]int{}, old...), x), true
+		}
+	}
+	for name, m := range maps() {
+		t.Run(name, func(t *testing.T) {
+			v, ok := m.Compute("a", appendValue(1))
+			assert.True(t, ok)
+			assert.Equal(t, []int{1}, v)
+			v, ok = m.Compute("a", appendValue(2))
+			assert.True(t, ok)
+			assert.Equal(t, []int{1, 2}, v)
+
+			v, ok = m.Compute("a", func(old []int, loaded bool) ([]int, bool) {
+				assert.True(t, loaded)
+				return nil, false
+			})
+			assert.False(t, ok)
+			assert.Nil(t, v)
+			_, ok = m.Load("a")
+			assert.False(t, ok)
+
+			v, ok = m.Compute("b", func(old []int, loaded bool) ([]int, bool) {
+				assert.False(t, loaded)
+				return []int{7}, false
+			})
+			assert.False(t, ok)
+			assert.Nil(t, v)
+			_, ok = m.Load("b")
+			assert.False(t, ok)
+
+			const goroutines, iterations = 8, 100
+			wg := sync.WaitGroup{}
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < iterations; j++ {
+						m.Compute("c", appendValue(j))
+					}
+				}()
+			}
+			wg.Wait()
+			v, _ = m.Load("c")
+			assert.Len(t, v, goroutines*iterations)
+		})
+	}
+}
+
+func TestShardedMap(t *testing.T) {
+	m := gsync.NewShardedMap[int, int](gsync.HashInt[int])
+	for i := 0; i < 100; i++ {
+		m.Store(i, i*i)
+	}
+	assert.Equal(t, 100, m.Len())
+	sum := 0
+	m.Range(func(k, v int) bool {
+		sum += v - k*k
+		return true
+	})
+	assert.Equal(t, 0, sum)
+	cnt := 0
+	m.Range(func(k, v int) bool {
+		cnt++
+		return cnt < 10
+	})
+	assert.Equal(t, 10, cnt)
+	assert.NotEqual(t, gsync.HashInt(1), gsync.HashInt(2))
+}
+
+type benchMap interface {
+	Load(int) (int, bool)
+	Store(int, int)
+}
+
+type lockedMap struct {
+	m *gsync.RWMutex[map[int]int]
+}
+
+func (lm lockedMap) Load(k int) (int, bool) {
+	m := lm.m.RLock()
+	defer lm.m.RUnlock()
+	v, ok := m[k]
+	return v, ok
+}
+
+func (lm lockedMap) Store(k int, v int) {
+	lm.m.WithLock(func(m *map[int]int) {
+		(*m)[k] = v
+	})
+}
+
+func benchmarkMaps(b *testing.B, writePercent int) {
+	const keys = 1 << 12
+	newMaps := map[string]func() benchMap{
+		"Map":        func() benchMap { return &gsync.Map[int, int]{} },
+		"ShardedMap": func() benchMap { return gsync.NewShardedMap[int, int](gsync.HashInt[int]) },
+		"RWMutex":    func() benchMap { return lockedMap{gsync.NewRWMutex(map[int]int{})} },
+	}
+	for _, name := range []string{"Map", "ShardedMap", "RWMutex"} {
+		b.Run(name, func(b *testing.B) {
+			m := newMaps[name]()
+			for i := 0; i < keys; i++ {
+				m.Store(i, i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					k := (i * 31) % keys
+					if i%100 < writePercent {
+						m.Store(k, i)
+					} else {
+						m.Load(k)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkMaps_ReadHeavy(b *testing.B) {
+	benchmarkMaps(b, 5)
+}
+
+func BenchmarkMaps_WriteHeavy(b *testing.B) {
+	benchmarkMaps(b, 80)
+}
+
+func BenchmarkHashString(b *testing.B) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gsync.HashString(keys[i%len(keys)])
+	}
+}
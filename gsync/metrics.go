@@ -0,0 +1,257 @@
+package gsync
+
+import (
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LockKind is a kind of acquired lock
+type LockKind int
+
+const (
+	// Exclusive is a lock acquired by Lock (and its variants)
+	Exclusive LockKind = iota
+	// Shared is a read lock of RWMutex acquired by RLock (and its variants)
+	Shared
+)
+
+func (k LockKind) String() string {
+	if k == Shared {
+		return "shared"
+	}
+	return "exclusive"
+}
+
+// LockObserver receives lock events of instrumented mutexes (see WithObserver)
+// implement it to export metrics into your metrics system
+// methods are called synchronously, so they must be fast
+type LockObserver interface {
+	// Acquired is called after lock is acquired. `wait` is a time spent waiting for the lock
+	Acquired(name string, kind LockKind, wait time.Duration)
+	// Released is called after lock is released. `hold` is a time the lock was held
+	// read locks are anonymous, so read unlock is matched with the oldest read lock acquisition:
+	// total hold time of read locks is exact, but hold time of single release is approximate when read locks overlap
+	Released(name string, kind LockKind, hold time.Duration)
+}
+
+// SlowLock describes exclusive lock held longer than threshold (see WithSlowLock)
+type SlowLock struct {
+	Name string
+	Hold time.Duration
+	// Stack is a stack trace of goroutine that acquired the lock
+	Stack []byte
+}
+
+// Option configures mutex created by NewMutex or NewRWMutex
+type Option func(*instrumentation)
+
+// WithName sets name of mutex passed to LockObserver and SlowLock callback
+func WithName(name string) Option {
+	return func(i *instrumentation) {
+		i.name = name
+	}
+}
+
+// WithObserver enables instrumentation of mutex: all lock events are reported to `observer`
+func WithObserver(observer LockObserver) Option {
+	return func(i *instrumentation) {
+		i.observer = observer
+	}
+}
+
+// WithSlowLock calls `f` after exclusive lock that was held longer than `threshold` is released
+// stack of goroutine that acquired the lock is captured on every acquire, so it slows down locking
+func WithSlowLock(threshold time.Duration, f func(SlowLock)) Option {
+	return func(i *instrumentation) {
+		i.slowThreshold = threshold
+		i.onSlow = f
+	}
+}
+
+type instrumentation struct {
+	name          string
+	observer      LockObserver
+	slowThreshold time.Duration
+	onSlow        func(SlowLock)
+	// acquiredAt and stack of current exclusive lock holder
+	acquiredAt time.Time
+	stack      []byte
+	// sharedAt are acquisition times of current read lock holders in order of acquisition
+	sharedMu sync.Mutex
+	sharedAt []time.Time
+}
+
+func newInstrumentation(opts []Option) *instrumentation {
+	if len(opts) == 0 {
+		return nil
+	}
+	inst := &instrumentation{}
+	for _, opt := range opts {
+		opt(inst)
+	}
+	if inst.observer == nil && inst.onSlow == nil {
+		return nil
+	}
+	return inst
+}
+
+func (i *instrumentation) acquired(kind LockKind, start time.Time) {
+	now := time.Now()
+	if i.observer != nil {
+		i.observer.Acquired(i.name, kind, now.Sub(start))
+	}
+	if kind == Exclusive {
+		i.acquiredAt = now
+		if i.onSlow != nil {
+			i.stack = debug.Stack()
+		}
+	}
+}
+
+// released must be called while lock is still held
+// returned func reports event and must be called after lock is released
+func (i *instrumentation) released() func() {
+	hold := time.Since(i.acquiredAt)
+	stack := i.stack
+	i.stack = nil
+	return func() {
+		if i.observer != nil {
+			i.observer.Released(i.name, Exclusive, hold)
+		}
+		if i.onSlow != nil && hold > i.slowThreshold {
+			i.onSlow(SlowLock{
+				Name:  i.name,
+				Hold:  hold,
+				Stack: stack,
+			})
+		}
+	}
+}
+
+// sharedAcquired records acquisition of read lock
+func (i *instrumentation) sharedAcquired() {
+	i.sharedMu.Lock()
+	defer i.sharedMu.Unlock()
+	i.sharedAt = append(i.sharedAt, time.Now())
+}
+
+// sharedReleased must be called after read lock is released
+// returned func reports event
+func (i *instrumentation) sharedReleased() func() {
+	i.sharedMu.Lock()
+	defer i.sharedMu.Unlock()
+	var hold time.Duration
+	if len(i.sharedAt) > 0 {
+		hold = time.Since(i.sharedAt[0])
+		i.sharedAt = i.sharedAt[1:]
+	}
+	if len(i.sharedAt) == 0 {
+		i.sharedAt = nil
+	}
+	return func() {
+		if i.observer != nil {
+			i.observer.Released(i.name, Shared, hold)
+		}
+	}
+}
+
+// DefaultWaitBuckets are upper bounds of wait time histogram buckets used by LockStats by default
+var DefaultWaitBuckets = []time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// LockStats is a LockObserver that collects lock statistics in memory
+// one LockStats can be shared by several mutexes
+type LockStats struct {
+	mu      sync.Mutex
+	buckets []time.Duration
+	stats   LockStatsSnapshot
+}
+
+// LockStatsSnapshot is a copy of statistics collected by LockStats
+type LockStatsSnapshot struct {
+	// Acquires is number of acquired exclusive locks
+	Acquires int
+	// SharedAcquires is number of acquired read locks
+	SharedAcquires int
+	// WaitBuckets are upper bounds of WaitHistogram buckets
+	WaitBuckets []time.Duration
+	// WaitHistogram has len(WaitBuckets)+1 elements: WaitHistogram[i] counts waits in range
+	// (WaitBuckets[i-1], WaitBuckets[i]], the last one counts waits longer than last bucket
+	WaitHistogram []int
+	TotalWait     time.Duration
+	// TotalHold and MaxHold are hold times of exclusive locks
+	TotalHold time.Duration
+	MaxHold   time.Duration
+	// SharedTotalHold and SharedMaxHold are hold times of read locks (see LockObserver.Released)
+	SharedTotalHold time.Duration
+	SharedMaxHold   time.Duration
+}
+
+// NewLockStats returns new LockStats
+// optional argument `buckets` sets upper bounds of wait histogram buckets (DefaultWaitBuckets by default)
+func NewLockStats(buckets ...time.Duration) *LockStats {
+	if len(buckets) == 0 {
+		buckets = DefaultWaitBuckets
+	}
+	buckets = append([]time.Duration{}, buckets...)
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i] < buckets[j]
+	})
+	return &LockStats{
+		buckets: buckets,
+		stats: LockStatsSnapshot{
+			WaitBuckets:   buckets,
+			WaitHistogram: make([]int, len(buckets)+1),
+		},
+	}
+}
+
+func (ls *LockStats) Acquired(_ string, kind LockKind, wait time.Duration) {
+	idx := sort.Search(len(ls.buckets), func(i int) bool {
+		return wait <= ls.buckets[i]
+	})
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if kind == Shared {
+		ls.stats.SharedAcquires++
+	} else {
+		ls.stats.Acquires++
+	}
+	ls.stats.WaitHistogram[idx]++
+	ls.stats.TotalWait += wait
+}
+
+func (ls *LockStats) Released(_ string, kind LockKind, hold time.Duration) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if kind == Shared {
+		ls.stats.SharedTotalHold += hold
+		if hold > ls.stats.SharedMaxHold {
+			ls.stats.SharedMaxHold = hold
+		}
+		return
+	}
+	ls.stats.TotalHold += hold
+	if hold > ls.stats.MaxHold {
+		ls.stats.MaxHold = hold
+	}
+}
+
+// Snapshot returns copy of collected statistics
+func (ls *LockStats) Snapshot() LockStatsSnapshot {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	s := ls.stats
+	s.WaitBuckets = append([]time.Duration{}, s.WaitBuckets...)
+	s.WaitHistogram = append([]int{}, s.WaitHistogram...)
+	return s
+}
@@ -0,0 +1,119 @@
+package gsync_test
+
+import (
+	"gtools/gsync"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingObserver struct {
+	mu       sync.Mutex
+	names    []string
+	kinds    []gsync.LockKind
+	releases []gsync.LockKind
+}
+
+func (ro *recordingObserver) Acquired(name string, kind gsync.LockKind, _ time.Duration) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.names = append(ro.names, name)
+	ro.kinds = append(ro.kinds, kind)
+}
+
+func (ro *recordingObserver) Released(_ string, kind gsync.LockKind, _ time.Duration) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.releases = append(ro.releases, kind)
+}
+
+func TestMutex_Observer(t *testing.T) {
+	observer := &recordingObserver{}
+	m := gsync.NewRWMutex(0, gsync.WithName("counter"), gsync.WithObserver(observer))
+	m.Lock()
+	m.Unlock()
+	m.RLock()
+	m.RUnlock()
+	m.Set(1)
+	_, ok := m.TryRLock()
+	assert.True(t, ok)
+	_, ok = m.TryLock()
+	assert.False(t, ok)
+	m.RUnlock()
+
+	assert.Equal(t, []string{"counter", "counter", "counter", "counter"}, observer.names)
+	assert.Equal(t, []gsync.LockKind{gsync.Exclusive, gsync.Shared, gsync.Exclusive, gsync.Shared}, observer.kinds)
+	assert.Equal(t, []gsync.LockKind{gsync.Exclusive, gsync.Shared, gsync.Exclusive, gsync.Shared}, observer.releases)
+}
+
+func TestLockStats(t *testing.T) {
+	stats := gsync.NewLockStats(time.Millisecond, 50*time.Millisecond)
+	m := gsync.NewMutex(0, gsync.WithObserver(stats))
+
+	m.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Lock()
+		m.Unlock()
+	}()
+	time.Sleep(5 * time.Millisecond)
+	m.Unlock()
+	<-done
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, 2, snapshot.Acquires)
+	assert.Equal(t, 0, snapshot.SharedAcquires)
+	assert.Equal(t, []time.Duration{time.Millisecond, 50 * time.Millisecond}, snapshot.WaitBuckets)
+	assert.Equal(t, 2, snapshot.WaitHistogram[0]+snapshot.WaitHistogram[1]+snapshot.WaitHistogram[2])
+	assert.GreaterOrEqual(t, snapshot.WaitHistogram[1]+snapshot.WaitHistogram[2], 1)
+	assert.GreaterOrEqual(t, snapshot.TotalWait, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, snapshot.MaxHold, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, snapshot.TotalHold, snapshot.MaxHold)
+
+	// snapshot is a copy
+	snapshot.WaitHistogram[0] = 100
+	assert.NotEqual(t, 100, stats.Snapshot().WaitHistogram[0])
+}
+
+func TestLockStats_Shared(t *testing.T) {
+	stats := gsync.NewLockStats()
+	m := gsync.NewRWMutex(0, gsync.WithObserver(stats))
+	m.RLock()
+	m.RLock()
+	time.Sleep(5 * time.Millisecond)
+	m.RUnlock()
+	m.RUnlock()
+	_, ok := m.TryRLock()
+	assert.True(t, ok)
+	m.RUnlock()
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, 3, snapshot.SharedAcquires)
+	assert.Equal(t, time.Duration(0), snapshot.TotalHold)
+	assert.GreaterOrEqual(t, snapshot.SharedMaxHold, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, snapshot.SharedTotalHold, 10*time.Millisecond)
+}
+
+func holdSlowLock(m *gsync.Mutex[int]) {
+	m.Lock()
+	time.Sleep(20 * time.Millisecond)
+	m.Unlock()
+}
+
+func TestMutex_SlowLock(t *testing.T) {
+	var reports []gsync.SlowLock
+	m := gsync.NewMutex(0, gsync.WithName("slow"), gsync.WithSlowLock(10*time.Millisecond, func(sl gsync.SlowLock) {
+		reports = append(reports, sl)
+	}))
+	m.Set(1)
+	assert.Empty(t, reports)
+
+	holdSlowLock(m)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, "slow", reports[0].Name)
+	assert.GreaterOrEqual(t, reports[0].Hold, 20*time.Millisecond)
+	assert.Contains(t, string(reports[0].Stack), "holdSlowLock")
+}
@@ -1,30 +1,100 @@
 package gsync
 
-import "sync"
+import (
+	"context"
+	"time"
+)
 
 type Mutex[T any] struct {
-	mutex *sync.Mutex
+	mutex *rwLock
 	val   *T
 }
 
-func NewMutex[T any](t T) *Mutex[T] {
+// NewMutex returns new Mutex protecting value `t`
+// optional arguments `opts` enable instrumentation (see WithObserver and WithSlowLock)
+func NewMutex[T any](t T, opts ...Option) *Mutex[T] {
 	m := &Mutex[T]{
 		val:   &t,
-		mutex: &sync.Mutex{},
+		mutex: newRWLock(opts),
 	}
 	return m
 }
 
 func (m *Mutex[T]) Lock() *T {
-	m.mutex.Lock()
+	m.mutex.lock()
 	return m.val
 }
 
 func (m *Mutex[_]) Unlock() {
-	m.mutex.Unlock()
+	m.mutex.unlock()
 }
 
+// TryLock tries to lock mutex and returns pointer to protected value and true on success
+// if mutex is already locked it returns nil and false
 func (m *Mutex[T]) TryLock() (*T, bool) {
-	ok := m.mutex.TryLock()
-	return m.val, ok
+	if !m.mutex.tryLock() {
+		return nil, false
+	}
+	return m.val, true
+}
+
+// TryLockFor same as TryLock but waits for the lock at most `d`
+func (m *Mutex[T]) TryLockFor(d time.Duration) (*T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	val, err := m.LockCtx(ctx)
+	return val, err == nil
+}
+
+// LockCtx same as Lock but stops waiting when `ctx` is done
+// returns nil and ctx.Err() if lock was not acquired
+func (m *Mutex[T]) LockCtx(ctx context.Context) (*T, error) {
+	if err := m.mutex.lockCtx(ctx); err != nil {
+		return nil, err
+	}
+	return m.val, nil
+}
+
+// WithLock calls `f` with pointer to protected value under lock
+// `f` must not keep the pointer after return
+func (m *Mutex[T]) WithLock(f func(*T)) {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	f(m.val)
+}
+
+// WithLockErr same as WithLock but returns error of `f`
+func (m *Mutex[T]) WithLockErr(f func(*T) error) error {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	return f(m.val)
+}
+
+// Get returns copy of protected value
+func (m *Mutex[T]) Get() T {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	return *m.val
+}
+
+// Set replaces protected value with `t`
+func (m *Mutex[T]) Set(t T) {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	*m.val = t
+}
+
+// Update replaces protected value with result of `f` call on it and returns new value
+func (m *Mutex[T]) Update(f func(T) T) T {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	*m.val = f(*m.val)
+	return *m.val
+}
+
+// LockGuard locks mutex and returns Guard to access protected value
+// Guard must be unlocked by its Unlock method
+func (m *Mutex[T]) LockGuard() *Guard[T] {
+	m.mutex.lock()
+	return newGuard(m.val, m.mutex.unlock)
 }
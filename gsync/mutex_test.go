@@ -1,8 +1,12 @@
 package gsync_test
 
 import (
+	"context"
+	"errors"
 	"gtools/gsync"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -26,14 +30,14 @@ func TestMutex_Simple(t *testing.T) {
 	assert.Equal(t, 123, *val)
 	v, ok := lock.TryLock()
 	assert.False(t, ok)
-	assert.Equal(t, *val, *v)
+	assert.Nil(t, v)
 	lock.Unlock()
 
 	val = lock.Lock()
 	*val = 42
 	v, ok = lock.TryLock()
 	assert.False(t, ok)
-	assert.Equal(t, *val, *v)
+	assert.Nil(t, v)
 	lock.Unlock()
 	assert.Equal(t, 42, *s.protected.Lock())
 	s.protected.Unlock()
@@ -51,14 +55,14 @@ func TestMutex_Ptr(t *testing.T) {
 	assert.Equal(t, 123, **val)
 	v, ok := lock.TryLock()
 	assert.False(t, ok)
-	assert.Equal(t, *val, *v)
+	assert.Nil(t, v)
 	lock.Unlock()
 
 	val = lock.Lock()
 	**val = 42
 	v, ok = lock.TryLock()
 	assert.False(t, ok)
-	assert.Equal(t, *val, *v)
+	assert.Nil(t, v)
 	lock.Unlock()
 	assert.Equal(t, 42, **s.protected.Lock())
 	s.protected.Unlock()
@@ -76,15 +80,125 @@ func TestMutex_Nil(t *testing.T) {
 	assert.Nil(t, *val)
 	v, ok := lock.TryLock()
 	assert.False(t, ok)
-	assert.Equal(t, *val, *v)
+	assert.Nil(t, v)
 	lock.Unlock()
 
 	val = lock.Lock()
 	*val = &a
 	v, ok = lock.TryLock()
 	assert.False(t, ok)
-	assert.Equal(t, *val, *v)
+	assert.Nil(t, v)
 	lock.Unlock()
 	assert.Equal(t, 42, **s.protected.Lock())
 	s.protected.Unlock()
 }
+
+func TestMutex_Scoped(t *testing.T) {
+	m := gsync.NewMutex([]int{1})
+	m.WithLock(func(v *[]int) {
+		*v = append(*v, 2)
+	})
+	assert.Equal(t, []int{1, 2}, m.Get())
+
+	errTest := errors.New("test")
+	err := m.WithLockErr(func(v *[]int) error {
+		*v = append(*v, 3)
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, []int{1, 2, 3}, m.Get())
+
+	m.Set([]int{5})
+	assert.Equal(t, []int{5}, m.Get())
+	assert.Equal(t, []int{5, 6}, m.Update(func(v []int) []int {
+		return append(v, 6)
+	}))
+
+	// lock is released after all calls
+	_, ok := m.TryLock()
+	assert.True(t, ok)
+	m.Unlock()
+}
+
+func TestMutex_WithLockPanic(t *testing.T) {
+	m := gsync.NewMutex(1)
+	assert.Panics(t, func() {
+		m.WithLock(func(*int) {
+			panic("test")
+		})
+	})
+	// lock is released after panic
+	_, ok := m.TryLock()
+	assert.True(t, ok)
+	m.Unlock()
+}
+
+func TestMutex_Concurrent(t *testing.T) {
+	const goroutines, iterations = 8, 1000
+	m := gsync.NewMutex(0)
+	wg := sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				m.Update(func(v int) int {
+					return v + 1
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, goroutines*iterations, m.Get())
+}
+
+func TestMutex_TryLockFor(t *testing.T) {
+	m := gsync.NewMutex(1)
+	val := m.Lock()
+
+	v, ok := m.TryLockFor(10 * time.Millisecond)
+	assert.False(t, ok)
+	assert.Nil(t, v)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		*val = 2
+		m.Unlock()
+	}()
+	v, ok = m.TryLockFor(time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 2, *v)
+	m.Unlock()
+}
+
+func TestMutex_LockCtx(t *testing.T) {
+	m := gsync.NewMutex(1)
+	v, err := m.LockCtx(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *v)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	v, err = m.LockCtx(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, v)
+	m.Unlock()
+
+	// already cancelled context
+	v, err = m.LockCtx(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, v)
+	_, ok := m.TryLock()
+	assert.True(t, ok)
+	m.Unlock()
+}
+
+func TestMutex_UnlockOfUnlocked(t *testing.T) {
+	m := gsync.NewMutex(1)
+	assert.Panics(t, func() {
+		m.Unlock()
+	})
+}
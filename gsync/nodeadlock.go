@@ -0,0 +1,21 @@
+//go:build !gsync_deadlock
+
+package gsync
+
+// DeadlockDetection is true when package is built with `gsync_deadlock` tag
+// in this case all locks and unlocks of Mutex and RWMutex are tracked to find potential deadlocks
+// (acquisitions that can not wait forever such as TryLock or LockCtx are tracked but not checked,
+// read lock acquired while holding other read lock is not checked either)
+const DeadlockDetection = false
+
+type lockTracker struct{}
+
+func newLockTracker(uint64) *lockTracker {
+	return nil
+}
+
+func detectBeforeLock(uint64, LockKind) {}
+
+func detectLocked(uint64, LockKind) {}
+
+func detectUnlocked(uint64) {}
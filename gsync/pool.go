@@ -0,0 +1,149 @@
+package gsync
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Pool is a typed wrapper over sync.Pool
+// zero Pool is ready to use, it creates new(T) when pool is empty
+type Pool[T any] struct {
+	pool  sync.Pool
+	new   func() *T
+	reset func(*T)
+	keep  func(*T) bool
+	// stats is nil unless WithStats is used
+	stats *poolCounters
+}
+
+// poolCounters are shared by all goroutines, so updating them on every Get and Put costs contention
+type poolCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	puts   atomic.Uint64
+	drops  atomic.Uint64
+}
+
+// PoolOption configures Pool created by NewPool
+type PoolOption[T any] func(*Pool[T])
+
+// WithReset sets func that is called on every Put to clear value before it is reused
+func WithReset[T any](reset func(*T)) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.reset = reset
+	}
+}
+
+// WithMaxCap sets max capacity of values kept in the pool
+// values which `capOf` is greater than `max` are dropped by Put, so one huge buffer does not stay in memory forever
+func WithMaxCap[T any](max int, capOf func(*T) int) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.keep = func(t *T) bool {
+			return capOf(t) <= max
+		}
+	}
+}
+
+// WithStats enables collection of Pool statistics (see Stats)
+// counters are shared by all goroutines, so it slows down Get and Put under high concurrency
+func WithStats[T any]() PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.stats = &poolCounters{}
+	}
+}
+
+// NewPool returns new Pool that uses `new` to create values when pool is empty (new(T) if `new` is nil)
+func NewPool[T any](new func() *T, opts ...PoolOption[T]) *Pool[T] {
+	p := &Pool[T]{
+		new: new,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// NewSlicePool returns Pool of slices created with capacity `size`
+// slices are truncated to zero length on Put, slices with capacity greater than `maxCap` are dropped
+// optional `opts` are applied after default ones
+//
+//	buf := pool.Get()
+//	defer pool.Put(buf)
+//	*buf = append(*buf, data...)
+func NewSlicePool[E any](size int, maxCap int, opts ...PoolOption[[]E]) *Pool[[]E] {
+	return NewPool(func() *[]E {
+		s := make([]E, 0, size)
+		return &s
+	}, append([]PoolOption[[]E]{
+		WithReset(func(s *[]E) {
+			*s = (*s)[:0]
+		}),
+		WithMaxCap(maxCap, func(s *[]E) int {
+			return cap(*s)
+		}),
+	}, opts...)...)
+}
+
+// Get returns value from the pool or creates new one
+func (p *Pool[T]) Get() *T {
+	if v := p.pool.Get(); v != nil {
+		if p.stats != nil {
+			p.stats.hits.Add(1)
+		}
+		return v.(*T)
+	}
+	if p.stats != nil {
+		p.stats.misses.Add(1)
+	}
+	if p.new != nil {
+		return p.new()
+	}
+	return new(T)
+}
+
+// Put resets value and returns it to the pool, `t` must not be used after Put
+// nil and oversized values (see WithMaxCap) are dropped
+func (p *Pool[T]) Put(t *T) {
+	if t == nil {
+		return
+	}
+	if p.keep != nil && !p.keep(t) {
+		if p.stats != nil {
+			p.stats.drops.Add(1)
+		}
+		return
+	}
+	if p.reset != nil {
+		p.reset(t)
+	}
+	if p.stats != nil {
+		p.stats.puts.Add(1)
+	}
+	p.pool.Put(t)
+}
+
+// PoolStats is a statistics of Pool usage
+type PoolStats struct {
+	// Hits is number of Get calls that reused value from the pool
+	Hits uint64
+	// Misses is number of Get calls that created new value
+	Misses uint64
+	// Puts is number of values returned to the pool
+	Puts uint64
+	// Drops is number of values dropped by Put because of max capacity
+	Drops uint64
+}
+
+// Stats returns statistics of Pool usage
+// statistics are collected only if Pool is created with WithStats option, otherwise zero PoolStats is returned
+func (p *Pool[T]) Stats() PoolStats {
+	if p.stats == nil {
+		return PoolStats{}
+	}
+	return PoolStats{
+		Hits:   p.stats.hits.Load(),
+		Misses: p.stats.misses.Load(),
+		Puts:   p.stats.puts.Load(),
+		Drops:  p.stats.drops.Load(),
+	}
+}
@@ -0,0 +1,145 @@
+package gsync_test
+
+import (
+	"bytes"
+	"gtools/gsync"
+	"runtime/debug"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// disableGC prevents pool from being cleared during test
+func disableGC(t *testing.T) {
+	percent := debug.SetGCPercent(-1)
+	t.Cleanup(func() {
+		debug.SetGCPercent(percent)
+	})
+}
+
+func TestPool_Simple(t *testing.T) {
+	disableGC(t)
+	created := 0
+	p := gsync.NewPool(func() *bytes.Buffer {
+		created++
+		return &bytes.Buffer{}
+	}, gsync.WithReset((*bytes.Buffer).Reset), gsync.WithStats[bytes.Buffer]())
+	buf := p.Get()
+	buf.WriteString("hello")
+	p.Put(buf)
+	p.Put(nil)
+	buf = p.Get()
+	assert.Equal(t, 0, buf.Len())
+
+	stats := p.Stats()
+	// sync.Pool may drop values (e.g. under race detector), so hit is not guaranteed
+	assert.Equal(t, uint64(created), stats.Misses)
+	assert.Equal(t, uint64(2), stats.Hits+stats.Misses)
+	assert.Equal(t, uint64(1), stats.Puts)
+}
+
+func TestPool_Zero(t *testing.T) {
+	var p gsync.Pool[int]
+	v := p.Get()
+	assert.NotNil(t, v)
+	assert.Equal(t, 0, *v)
+	p.Put(v)
+	// stats are disabled by default
+	assert.Equal(t, gsync.PoolStats{}, p.Stats())
+}
+
+func TestSlicePool(t *testing.T) {
+	disableGC(t)
+	p := gsync.NewSlicePool[byte](16, 64, gsync.WithStats[[]byte]())
+	buf := p.Get()
+	assert.Equal(t, 0, len(*buf))
+	assert.Equal(t, 16, cap(*buf))
+	*buf = append(*buf, "data"...)
+	p.Put(buf)
+
+	buf = p.Get()
+	assert.Equal(t, 0, len(*buf))
+	*buf = append(*buf, make([]byte, 100)...)
+	p.Put(buf)
+	assert.Equal(t, uint64(1), p.Stats().Drops)
+	assert.Equal(t, uint64(1), p.Stats().Puts)
+}
+
+func TestPool_Concurrent(t *testing.T) {
+	p := gsync.NewSlicePool[int](0, 1024, gsync.WithStats[[]int]())
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s := p.Get()
+				assert.Empty(t, *s)
+				*s = append(*s, i, j)
+				p.Put(s)
+			}
+		}(i)
+	}
+	wg.Wait()
+	stats := p.Stats()
+	assert.Equal(t, uint64(800), stats.Hits+stats.Misses)
+	assert.Equal(t, uint64(800), stats.Puts)
+}
+
+func BenchmarkPool_Typed(b *testing.B) {
+	p := gsync.NewPool(func() *bytes.Buffer {
+		return &bytes.Buffer{}
+	}, gsync.WithReset((*bytes.Buffer).Reset))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := p.Get()
+			buf.WriteString("hello")
+			p.Put(buf)
+		}
+	})
+}
+
+func BenchmarkPool_TypedStats(b *testing.B) {
+	p := gsync.NewPool(func() *bytes.Buffer {
+		return &bytes.Buffer{}
+	}, gsync.WithReset((*bytes.Buffer).Reset), gsync.WithStats[bytes.Buffer]())
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := p.Get()
+			buf.WriteString("hello")
+			p.Put(buf)
+		}
+	})
+}
+
+func BenchmarkPool_Raw(b *testing.B) {
+	p := sync.Pool{
+		New: func() any {
+			return &bytes.Buffer{}
+		},
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := p.Get().(*bytes.Buffer)
+			buf.WriteString("hello")
+			buf.Reset()
+			p.Put(buf)
+		}
+	})
+}
+
+func BenchmarkPool_Slice(b *testing.B) {
+	p := gsync.NewSlicePool[byte](64, 1024)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := p.Get()
+			*buf = append(*buf, "hello"...)
+			p.Put(buf)
+		}
+	})
+}
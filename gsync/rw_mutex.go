@@ -1,44 +1,151 @@
 package gsync
 
-import "sync"
+import (
+	"context"
+	"time"
+)
 
 type RWMutex[T any] struct {
-	mutex *sync.RWMutex
+	mutex *rwLock
 	val   *T
 }
 
-func NewRWMutex[T any](t T) *RWMutex[T] {
+// NewRWMutex returns new RWMutex protecting value `t`
+// optional arguments `opts` enable instrumentation (see WithObserver and WithSlowLock)
+func NewRWMutex[T any](t T, opts ...Option) *RWMutex[T] {
 	m := &RWMutex[T]{
 		val:   &t,
-		mutex: &sync.RWMutex{},
+		mutex: newRWLock(opts),
 	}
 	return m
 }
 
 func (m *RWMutex[T]) Lock() *T {
-	m.mutex.Lock()
+	m.mutex.lock()
 	return m.val
 }
 
 func (m *RWMutex[T]) Unlock() {
-	m.mutex.Unlock()
+	m.mutex.unlock()
 }
 
 func (m *RWMutex[T]) RLock() T {
-	m.mutex.RLock()
+	m.mutex.rlock()
 	return *m.val
 }
 
 func (m *RWMutex[T]) RUnlock() {
-	m.mutex.RUnlock()
+	m.mutex.runlock()
 }
 
+// TryLock tries to write lock mutex and returns pointer to protected value and true on success
+// if mutex is already locked it returns nil and false
 func (m *RWMutex[T]) TryLock() (*T, bool) {
-	ok := m.mutex.TryLock()
-	return m.val, ok
+	if !m.mutex.tryLock() {
+		return nil, false
+	}
+	return m.val, true
 }
 
+// TryRLock tries to read lock mutex and returns copy of protected value and true on success
+// if mutex is write locked (or writer waits for it) it returns zero value and false
 func (m *RWMutex[T]) TryRLock() (T, bool) {
-	ok := m.mutex.TryRLock()
-	return *m.val, ok
+	if !m.mutex.tryRLock() {
+		var t T
+		return t, false
+	}
+	return *m.val, true
+}
+
+// TryRLockFor same as TryRLock but waits for the lock at most `d`
+func (m *RWMutex[T]) TryRLockFor(d time.Duration) (T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	val, err := m.RLockCtx(ctx)
+	return val, err == nil
+}
+
+// RLockCtx same as RLock but stops waiting when `ctx` is done
+// returns zero value and ctx.Err() if lock was not acquired
+func (m *RWMutex[T]) RLockCtx(ctx context.Context) (T, error) {
+	if err := m.mutex.rlockCtx(ctx); err != nil {
+		var t T
+		return t, err
+	}
+	return *m.val, nil
+}
+
+// TryLockFor same as TryLock but waits for the lock at most `d`
+func (m *RWMutex[T]) TryLockFor(d time.Duration) (*T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	val, err := m.LockCtx(ctx)
+	return val, err == nil
+}
+
+// LockCtx same as Lock but stops waiting when `ctx` is done
+// returns nil and ctx.Err() if lock was not acquired
+func (m *RWMutex[T]) LockCtx(ctx context.Context) (*T, error) {
+	if err := m.mutex.lockCtx(ctx); err != nil {
+		return nil, err
+	}
+	return m.val, nil
+}
+
+// WithLock calls `f` with pointer to protected value under write lock
+// `f` must not keep the pointer after return
+func (m *RWMutex[T]) WithLock(f func(*T)) {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	f(m.val)
+}
+
+// WithLockErr same as WithLock but returns error of `f`
+func (m *RWMutex[T]) WithLockErr(f func(*T) error) error {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	return f(m.val)
+}
+
+// WithRLock calls `f` with protected value under read lock
+func (m *RWMutex[T]) WithRLock(f func(T)) {
+	m.mutex.rlock()
+	defer m.mutex.runlock()
+	f(*m.val)
+}
+
+// Get returns copy of protected value (under read lock)
+func (m *RWMutex[T]) Get() T {
+	m.mutex.rlock()
+	defer m.mutex.runlock()
+	return *m.val
+}
+
+// Set replaces protected value with `t`
+func (m *RWMutex[T]) Set(t T) {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	*m.val = t
+}
+
+// Update replaces protected value with result of `f` call on it and returns new value
+func (m *RWMutex[T]) Update(f func(T) T) T {
+	m.mutex.lock()
+	defer m.mutex.unlock()
+	*m.val = f(*m.val)
+	return *m.val
+}
+
+// LockGuard write locks mutex and returns Guard to access protected value
+// Guard must be unlocked by its Unlock method
+func (m *RWMutex[T]) LockGuard() *Guard[T] {
+	m.mutex.lock()
+	return newGuard(m.val, m.mutex.unlock)
+}
+
+// RLockGuard read locks mutex and returns RGuard to read protected value
+// RGuard must be unlocked by its Unlock method
+func (m *RWMutex[T]) RLockGuard() *RGuard[T] {
+	m.mutex.rlock()
+	return &RGuard[T]{guard: newGuard(m.val, m.mutex.runlock)}
 }
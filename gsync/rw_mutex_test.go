@@ -1,8 +1,11 @@
 package gsync_test
 
 import (
+	"context"
+	"errors"
 	"gtools/gsync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -50,3 +53,120 @@ func Test_RWMutexExampleRLock(t *testing.T) {
 	assert.Equal(t, 42, *s.protected.Lock())
 	s.protected.Unlock()
 }
+
+func TestRWMutex_Scoped(t *testing.T) {
+	m := gsync.NewRWMutex(map[string]int{})
+	m.WithLock(func(v *map[string]int) {
+		(*v)["a"] = 1
+	})
+	m.WithRLock(func(v map[string]int) {
+		assert.Equal(t, 1, v["a"])
+	})
+
+	errTest := errors.New("test")
+	err := m.WithLockErr(func(v *map[string]int) error {
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+
+	m.Set(map[string]int{"b": 2})
+	assert.Equal(t, map[string]int{"b": 2}, m.Get())
+	m.Update(func(v map[string]int) map[string]int {
+		return map[string]int{"c": 3}
+	})
+	assert.Equal(t, map[string]int{"c": 3}, m.Get())
+
+	_, ok := m.TryLock()
+	assert.True(t, ok)
+	m.Unlock()
+}
+
+func TestRWMutex_TryLock(t *testing.T) {
+	m := gsync.NewRWMutex(1)
+	val := m.Lock()
+
+	v, ok := m.TryLock()
+	assert.False(t, ok)
+	assert.Nil(t, v)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// value is not read without lock, so there is no data race with writer
+		r, ok := m.TryRLock()
+		assert.False(t, ok)
+		assert.Equal(t, 0, r)
+	}()
+	*val = 2
+	<-done
+	m.Unlock()
+
+	r, ok := m.TryRLock()
+	assert.True(t, ok)
+	assert.Equal(t, 2, r)
+	v, ok = m.TryLock()
+	assert.False(t, ok)
+	assert.Nil(t, v)
+	m.RUnlock()
+}
+
+func TestRWMutex_TryLockFor(t *testing.T) {
+	m := gsync.NewRWMutex(1)
+	m.RLock()
+
+	v, ok := m.TryLockFor(10 * time.Millisecond)
+	assert.False(t, ok)
+	assert.Nil(t, v)
+	// cancelled writer does not block readers
+	_, ok = m.TryRLock()
+	assert.True(t, ok)
+	m.RUnlock()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		m.RUnlock()
+	}()
+	v, ok = m.TryLockFor(time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 1, *v)
+	m.Unlock()
+}
+
+func TestRWMutex_LockCtx(t *testing.T) {
+	m := gsync.NewRWMutex(1)
+	m.RLock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	v, err := m.LockCtx(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, v)
+	m.RUnlock()
+
+	v, err = m.LockCtx(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *v)
+	m.Unlock()
+}
+
+func TestRWMutex_WriterPreferred(t *testing.T) {
+	m := gsync.NewRWMutex(1)
+	m.RLock()
+	locked := make(chan struct{})
+	go func() {
+		*m.Lock() = 2
+		close(locked)
+		m.Unlock()
+	}()
+	// wait until writer is waiting
+	for {
+		if _, ok := m.TryRLock(); !ok {
+			break
+		}
+		m.RUnlock()
+		time.Sleep(time.Millisecond)
+	}
+	m.RUnlock()
+	<-locked
+	assert.Equal(t, 2, m.RLock())
+	m.RUnlock()
+}
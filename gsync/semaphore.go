@@ -0,0 +1,277 @@
+package gsync
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"gtools/ft"
+	"gtools/result"
+)
+
+// Semaphore is a weighted semaphore: it limits total weight (e.g. bytes of memory) of concurrent operations
+// waiters are served in FIFO order, so small requests can not starve large ones
+type Semaphore struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+type semWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewSemaphore returns Semaphore with maximum combined weight `size`
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{
+		size: size,
+	}
+}
+
+// Acquire acquires semaphore with weight `n`, blocking until resources are available or `ctx` is done
+// on failure returns ctx.Err() and leaves semaphore unchanged
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if n > s.size {
+		// can never succeed
+		s.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// acquired after ctx was done, give resources back
+			s.cur -= n
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			if !isFront {
+				s.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		// waiters blocked by this one may continue
+		s.notifyWaiters()
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires semaphore with weight `n` without blocking, returns true on success
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release releases semaphore with weight `n`
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("gsync: semaphore released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// notifyWaiters wakes up waiters in FIFO order while there are resources for them. Must be called with mu held
+func (s *Semaphore) notifyWaiters() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+		w := next.Value.(semWaiter)
+		if s.size-s.cur < w.n {
+			// next waiter does not fit, wake up nobody to not starve it
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
+
+type parallelItem[K any] struct {
+	// done is closed when result is ready
+	done chan struct{}
+	res  result.Result[K]
+	// weight is still held by the item, it is 0 after release. Guarded by parallelIter.mu
+	weight int64
+}
+
+type parallelIter[K any] struct {
+	sem   *Semaphore
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue []*parallelItem[K]
+	// done is set when producer is finished
+	done bool
+	// cancelled is set when context is done: weights of unconsumed items are released without waiting for consumer
+	cancelled bool
+	// stop is closed when iterator is exhausted
+	stop    chan struct{}
+	stopped bool
+}
+
+// release returns weight of item to semaphore. Must be called with mu held
+func (pi *parallelIter[K]) release(item *parallelItem[K]) {
+	if item.weight > 0 {
+		pi.sem.Release(item.weight)
+		item.weight = 0
+	}
+}
+
+func (pi *parallelIter[K]) push(item *parallelItem[K]) {
+	pi.mu.Lock()
+	pi.queue = append(pi.queue, item)
+	pi.mu.Unlock()
+	pi.cond.Signal()
+}
+
+func (pi *parallelIter[K]) finish() {
+	pi.mu.Lock()
+	pi.done = true
+	pi.mu.Unlock()
+	pi.cond.Signal()
+}
+
+// resolve sets result of item
+func (pi *parallelIter[K]) resolve(item *parallelItem[K], res result.Result[K]) {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	item.res = res
+	if pi.cancelled {
+		pi.release(item)
+	}
+	close(item.done)
+}
+
+// cancel releases weights of ready items that are not consumed yet
+// the rest are released by resolve
+func (pi *parallelIter[K]) cancel() {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	pi.cancelled = true
+	for _, item := range pi.queue {
+		select {
+		case <-item.done:
+			pi.release(item)
+		default:
+		}
+	}
+}
+
+func (pi *parallelIter[K]) Next() (result.Result[K], bool) {
+	pi.mu.Lock()
+	for len(pi.queue) == 0 && !pi.done {
+		pi.cond.Wait()
+	}
+	if len(pi.queue) == 0 {
+		if !pi.stopped {
+			pi.stopped = true
+			close(pi.stop)
+		}
+		pi.mu.Unlock()
+		return result.Result[K]{}, false
+	}
+	item := pi.queue[0]
+	pi.queue = pi.queue[1:]
+	pi.mu.Unlock()
+	<-item.done
+	pi.mu.Lock()
+	pi.release(item)
+	pi.mu.Unlock()
+	return item.res, true
+}
+
+// ParallelMap same as ft.MapResult but calls `mapper` concurrently, concurrency is bounded by semaphore `sem`:
+// each element acquires weight returned by `weight` (1 if `weight` is nil) until its result is consumed,
+// so semaphore also bounds memory of results that are ready but not consumed yet
+// weight greater than semaphore size is reduced to the size, so such element is processed alone
+// one semaphore may be shared by several pipelines to bound their total resource usage
+// results are yielded in order of source iterator, panic in `mapper` is converted into *PanicError
+// if `ctx` is done iterator yields results of already started calls and ctx.Err(),
+// weights of started calls are released as soon as they return without waiting for consumer,
+// so cancel `ctx` if you stop consuming returned iterator before it is exhausted
+//
+//	sem := gsync.NewSemaphore(64 << 20)
+//	iter := gsync.ParallelMap(ctx, files, sem, fileSize, readFile)
+//	contents := ft.Try(iter)
+func ParallelMap[T any, K any](
+	ctx context.Context,
+	iter ft.Iter[T],
+	sem *Semaphore,
+	weight func(T) int64,
+	mapper func(T) (K, error),
+) ft.Iter[result.Result[K]] {
+	pi := &parallelIter[K]{
+		sem:  sem,
+		stop: make(chan struct{}),
+	}
+	pi.cond = sync.NewCond(&pi.mu)
+	if done := ctx.Done(); done != nil {
+		go func() {
+			select {
+			case <-done:
+				pi.cancel()
+			case <-pi.stop:
+			}
+		}()
+	}
+	go func() {
+		defer pi.finish()
+		for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+			w := int64(1)
+			if weight != nil {
+				w = weight(next)
+			}
+			if w > sem.size {
+				// such weight can never be acquired, element takes the whole semaphore instead
+				w = sem.size
+			}
+			item := &parallelItem[K]{
+				done: make(chan struct{}),
+			}
+			if err := sem.Acquire(ctx, w); err != nil {
+				item.res = result.Err[K](err)
+				close(item.done)
+				pi.push(item)
+				return
+			}
+			item.weight = w
+			pi.push(item)
+			go func(t T) {
+				v, err := callRecover(func() (K, error) {
+					return mapper(t)
+				})
+				pi.resolve(item, result.Of(v, err))
+			}(next)
+		}
+	}()
+	return pi
+}
@@ -0,0 +1,190 @@
+package gsync_test
+
+import (
+	"context"
+	"errors"
+	"gtools/ft"
+	"gtools/gsync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func numbers(n int) []int {
+	res := make([]int, n)
+	for i := range res {
+		res[i] = i
+	}
+	return res
+}
+
+func TestSemaphore_Simple(t *testing.T) {
+	ctx := context.Background()
+	s := gsync.NewSemaphore(10)
+	assert.NoError(t, s.Acquire(ctx, 7))
+	assert.False(t, s.TryAcquire(4))
+	assert.True(t, s.TryAcquire(3))
+	s.Release(10)
+	assert.True(t, s.TryAcquire(10))
+	s.Release(10)
+	assert.Panics(t, func() {
+		s.Release(1)
+	})
+}
+
+func TestSemaphore_Cancel(t *testing.T) {
+	s := gsync.NewSemaphore(10)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	// weight greater than size can never be acquired
+	assert.ErrorIs(t, s.Acquire(ctx, 11), context.DeadlineExceeded)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, s.Acquire(cancelled, 1), context.Canceled)
+
+	assert.True(t, s.TryAcquire(5))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Acquire(ctx, 6), context.DeadlineExceeded)
+	// cancelled waiter does not block others
+	assert.True(t, s.TryAcquire(5))
+	s.Release(10)
+}
+
+func TestSemaphore_FIFO(t *testing.T) {
+	ctx := context.Background()
+	s := gsync.NewSemaphore(10)
+	assert.NoError(t, s.Acquire(ctx, 5))
+
+	large := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Acquire(ctx, 10))
+		close(large)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	// small request fits, but large one is waiting before it
+	assert.False(t, s.TryAcquire(1))
+
+	small := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Acquire(ctx, 1))
+		close(small)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-small:
+		t.Fatal("small request overtook large one")
+	default:
+	}
+
+	s.Release(5)
+	<-large
+	s.Release(10)
+	<-small
+	s.Release(1)
+	assert.True(t, s.TryAcquire(10))
+}
+
+func TestParallelMap(t *testing.T) {
+	ctx := context.Background()
+	sem := gsync.NewSemaphore(4)
+	var active, maxActive atomic.Int64
+	iter := gsync.ParallelMap(ctx, ft.SliceIter(numbers(50)), sem, func(i int) int64 {
+		return int64(i%2 + 1)
+	}, func(i int) (int, error) {
+		n := active.Add(int64(i%2 + 1))
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		active.Add(-int64(i%2 + 1))
+		return i * 2, nil
+	})
+	res := ft.Try(iter)
+	assert.Equal(t, ft.Collect(ft.Map(ft.SliceIter(numbers(50)), func(i int) int { return i * 2 })), ft.Collect[int](res))
+	assert.NoError(t, res.Err())
+	assert.LessOrEqual(t, maxActive.Load(), int64(4))
+	// all weights are released after results are consumed
+	assert.True(t, sem.TryAcquire(4))
+}
+
+func TestParallelMap_HeavyElement(t *testing.T) {
+	sem := gsync.NewSemaphore(4)
+	iter := ft.Try(gsync.ParallelMap(context.Background(), ft.SliceIter(numbers(3)), sem, func(i int) int64 {
+		return 10
+	}, func(i int) (int, error) {
+		return i, nil
+	}))
+	assert.Equal(t, []int{0, 1, 2}, ft.Collect[int](iter))
+	assert.NoError(t, iter.Err())
+	assert.True(t, sem.TryAcquire(4))
+}
+
+func TestParallelMap_Abandoned(t *testing.T) {
+	sem := gsync.NewSemaphore(4)
+	ctx, cancel := context.WithCancel(context.Background())
+	iter := gsync.ParallelMap(ctx, ft.SliceIter(numbers(8)), sem, nil, func(i int) (int, error) {
+		time.Sleep(time.Duration(i) * time.Millisecond)
+		return i, nil
+	})
+	v, ok := iter.Next()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v.Unwrap())
+	cancel()
+
+	// all weights are returned although iterator is not consumed
+	acquireCtx, acquireCancel := context.WithTimeout(context.Background(), time.Second)
+	defer acquireCancel()
+	assert.NoError(t, sem.Acquire(acquireCtx, 4))
+	sem.Release(4)
+
+	// results of started calls still can be consumed
+	res := ft.Collect(iter)
+	assert.NotEmpty(t, res)
+	assert.ErrorIs(t, res[len(res)-1].Err(), context.Canceled)
+	assert.True(t, sem.TryAcquire(4))
+}
+
+func TestParallelMap_Errors(t *testing.T) {
+	errBoom := errors.New("boom")
+	sem := gsync.NewSemaphore(2)
+	iter := ft.Try(gsync.ParallelMap(context.Background(), ft.SliceIter(numbers(5)), sem, nil, func(i int) (int, error) {
+		if i == 1 {
+			return 0, errBoom
+		}
+		if i == 3 {
+			panic("panic")
+		}
+		return i, nil
+	}), ft.SkipErrors)
+	assert.Equal(t, []int{0, 2, 4}, ft.Collect[int](iter))
+	assert.Len(t, iter.Errors(), 2)
+	assert.ErrorIs(t, iter.Err(), errBoom)
+	var pe *gsync.PanicError
+	assert.ErrorAs(t, iter.Errors()[1], &pe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res := ft.Collect(gsync.ParallelMap(ctx, ft.SliceIter(numbers(5)), sem, nil, func(i int) (int, error) {
+		return i, nil
+	}))
+	assert.Len(t, res, 1)
+	assert.ErrorIs(t, res[0].Err(), context.Canceled)
+}
+
+func BenchmarkSemaphore(b *testing.B) {
+	ctx := context.Background()
+	s := gsync.NewSemaphore(4)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = s.Acquire(ctx, 1)
+			s.Release(1)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package gsync
+
+import "reflect"
+
+// Cloner is implemented by types that can make deep copy of themselves
+// implement it if your type has unexported fields with pointers, slices or maps
+// or if reflection based copy is too slow
+// Clone is used for protected value and for every value nested in it (with value or pointer receiver)
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// RView is a read-only accessor of value protected by RWMutex
+// unlike RLock it gives deep copy of protected value (see Snapshot for what is copied),
+// so changes of the copy do not affect protected value
+type RView[T any] struct {
+	m *RWMutex[T]
+}
+
+// RView returns read-only accessor of protected value
+func (m *RWMutex[T]) RView() *RView[T] {
+	return &RView[T]{m: m}
+}
+
+// Read calls `f` with deep copy of protected value
+// so modifications made by `f` (even through slices, maps and pointers) do not affect protected value
+func (v *RView[T]) Read(f func(T)) {
+	f(v.m.Snapshot())
+}
+
+// Snapshot returns deep copy of protected value
+func (v *RView[T]) Snapshot() T {
+	return v.m.Snapshot()
+}
+
+// Snapshot returns deep copy of protected value made under read lock
+// values implementing Cloner (protected value itself or nested ones) are copied by their Clone method,
+// others are copied using reflection: pointers, slices, maps, arrays, interfaces and exported struct fields
+// are copied recursively. Unexported struct fields, channels and funcs are copied as is,
+// so memory referenced by them is shared with protected value (implement Cloner to copy it)
+func (m *RWMutex[T]) Snapshot() T {
+	m.mutex.rlock()
+	defer m.mutex.runlock()
+	return clone(*m.val)
+}
+
+func clone[T any](t T) T {
+	v := reflect.ValueOf(&t).Elem()
+	return deepCopy(v, make(map[visitedPtr]reflect.Value)).Interface().(T)
+}
+
+// visitedPtr identifies copied pointer. Type is a part of the key because
+// pointer to struct and pointer to its first field have the same address
+type visitedPtr struct {
+	p uintptr
+	t reflect.Type
+}
+
+// deepCopy returns deep copy of v
+// `visited` contains copies of already copied pointers to keep cycles and shared pointers
+func deepCopy(v reflect.Value, visited map[visitedPtr]reflect.Value) reflect.Value {
+	if c, ok := callClone(v); ok {
+		return c
+	}
+	result := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return result
+		}
+		key := visitedPtr{p: v.Pointer(), t: v.Type()}
+		if copied, ok := visited[key]; ok {
+			return copied
+		}
+		p := reflect.New(v.Type().Elem())
+		visited[key] = p
+		p.Elem().Set(deepCopy(v.Elem(), visited))
+		result.Set(p)
+	case reflect.Slice:
+		if v.IsNil() {
+			return result
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(deepCopy(v.Index(i), visited))
+		}
+		result.Set(s)
+	case reflect.Map:
+		if v.IsNil() {
+			return result
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(deepCopy(iter.Key(), visited), deepCopy(iter.Value(), visited))
+		}
+		result.Set(m)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			result.Index(i).Set(deepCopy(v.Index(i), visited))
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return result
+		}
+		result.Set(deepCopy(v.Elem(), visited))
+	case reflect.Struct:
+		result.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if result.Field(i).CanSet() {
+				result.Field(i).Set(deepCopy(v.Field(i), visited))
+			}
+		}
+	default:
+		result.Set(v)
+	}
+	return result
+}
+
+// callClone calls Clone method of v if its type (or pointer to it) implements Cloner
+func callClone(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Interface || !v.CanInterface() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return reflect.Value{}, false
+	}
+	t := v.Type()
+	if m, ok := t.MethodByName("Clone"); ok && isClone(m.Type, t) {
+		return v.Method(m.Index).Call(nil)[0], true
+	}
+	if m, ok := reflect.PointerTo(t).MethodByName("Clone"); ok && isClone(m.Type, t) {
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return p.Method(m.Index).Call(nil)[0], true
+	}
+	return reflect.Value{}, false
+}
+
+// isClone reports whether method type (with receiver) is `func() T`
+func isClone(method reflect.Type, t reflect.Type) bool {
+	return method.NumIn() == 1 && method.NumOut() == 1 && method.Out(0) == t
+}
@@ -0,0 +1,169 @@
+package gsync_test
+
+import (
+	"gtools/gsync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RLock returns shallow copy, so readers can modify protected value through it.
+// Snapshot and RView return deep copies that do not share memory with protected value.
+
+func TestRView_Slice(t *testing.T) {
+	m := gsync.NewRWMutex([]int{1, 2, 3})
+
+	shallow := m.RLock()
+	shallow[0] = 100
+	m.RUnlock()
+	assert.Equal(t, []int{100, 2, 3}, m.Get(), "RLock aliases protected slice")
+
+	m.RView().Read(func(v []int) {
+		v[0] = 1
+	})
+	snapshot := m.RView().Snapshot()
+	snapshot[1] = 200
+	assert.Equal(t, []int{100, 2, 3}, m.Get())
+}
+
+func TestRView_Map(t *testing.T) {
+	m := gsync.NewRWMutex(map[string][]int{"a": {1}})
+	m.RView().Read(func(v map[string][]int) {
+		v["a"][0] = 2
+		v["b"] = []int{3}
+	})
+	assert.Equal(t, map[string][]int{"a": {1}}, m.Get())
+}
+
+type viewNested struct {
+	Name  string
+	Tags  []string
+	Child *viewNested
+	Any   any
+	arr   [2]*int
+}
+
+func TestRView_Pointers(t *testing.T) {
+	one := 1
+	value := &viewNested{
+		Name:  "root",
+		Tags:  []string{"a"},
+		Child: &viewNested{Name: "child"},
+		Any:   map[int]int{1: 1},
+		arr:   [2]*int{&one},
+	}
+	m := gsync.NewRWMutex(value)
+	m.RView().Read(func(v *viewNested) {
+		v.Name = "changed"
+		v.Tags[0] = "changed"
+		v.Child.Name = "changed"
+		v.Any.(map[int]int)[1] = 2
+	})
+	assert.Equal(t, "root", value.Name)
+	assert.Equal(t, []string{"a"}, value.Tags)
+	assert.Equal(t, "child", value.Child.Name)
+	assert.Equal(t, map[int]int{1: 1}, value.Any)
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, value, snapshot)
+	assert.NotSame(t, value, snapshot)
+	// unexported fields are copied as is
+	assert.Same(t, value.arr[0], snapshot.arr[0])
+}
+
+func TestRView_Cycle(t *testing.T) {
+	value := &viewNested{Name: "a"}
+	value.Child = value
+	snapshot := gsync.NewRWMutex(value).Snapshot()
+	assert.NotSame(t, value, snapshot)
+	assert.Same(t, snapshot, snapshot.Child)
+}
+
+type clonerCounter struct {
+	data   []int
+	clones *int
+}
+
+func (c clonerCounter) Clone() clonerCounter {
+	*c.clones++
+	return clonerCounter{data: append([]int{}, c.data...), clones: c.clones}
+}
+
+func TestRView_Cloner(t *testing.T) {
+	clones := 0
+	m := gsync.NewRWMutex(clonerCounter{data: []int{1}, clones: &clones})
+	m.RView().Read(func(v clonerCounter) {
+		v.data[0] = 2
+	})
+	assert.Equal(t, 1, clones)
+	assert.Equal(t, []int{1}, m.Get().data)
+}
+
+type viewInner struct {
+	A int
+	B int
+}
+
+type viewOuter struct {
+	P *viewInner
+	Q *int
+}
+
+func TestRView_PointerToFirstField(t *testing.T) {
+	x := &viewInner{A: 1, B: 2}
+	m := gsync.NewRWMutex(viewOuter{P: x, Q: &x.A})
+	snapshot := m.Snapshot()
+	assert.Equal(t, viewInner{A: 1, B: 2}, *snapshot.P)
+	assert.Equal(t, 1, *snapshot.Q)
+	snapshot.P.A = 10
+	*snapshot.Q = 20
+	assert.Equal(t, viewInner{A: 1, B: 2}, *x)
+}
+
+type ptrCloner struct {
+	data []int
+}
+
+func (c *ptrCloner) Clone() ptrCloner {
+	return ptrCloner{data: append([]int{}, c.data...)}
+}
+
+type selfCloner struct {
+	data []int
+}
+
+func (c *selfCloner) Clone() *selfCloner {
+	return &selfCloner{data: append([]int{}, c.data...)}
+}
+
+type nestedCloners struct {
+	Counter clonerCounter
+	Ptr     ptrCloner
+	Self    *selfCloner
+	List    []ptrCloner
+}
+
+func TestRView_NestedCloner(t *testing.T) {
+	clones := 0
+	value := nestedCloners{
+		Counter: clonerCounter{data: []int{1}, clones: &clones},
+		Ptr:     ptrCloner{data: []int{2}},
+		Self:    &selfCloner{data: []int{3}},
+		List:    []ptrCloner{{data: []int{4}}},
+	}
+	snapshot := gsync.NewRWMutex(value).Snapshot()
+	assert.Equal(t, 1, clones)
+	snapshot.Counter.data[0] = 10
+	snapshot.Ptr.data[0] = 20
+	snapshot.Self.data[0] = 30
+	snapshot.List[0].data[0] = 40
+	assert.Equal(t, []int{1}, value.Counter.data)
+	assert.Equal(t, []int{2}, value.Ptr.data)
+	assert.Equal(t, []int{3}, value.Self.data)
+	assert.Equal(t, []int{4}, value.List[0].data)
+
+	// pointer receiver Clone of protected value itself
+	m := gsync.NewRWMutex(ptrCloner{data: []int{5}})
+	m.RView().Snapshot().data[0] = 50
+	assert.Equal(t, []int{5}, m.Get().data)
+}
@@ -0,0 +1,147 @@
+package gsync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Watched is a value which changes can be observed by subscribers (see Subscribe and WaitFor)
+// Load is lock-free, so it fits well for read-heavy values like config or feature state
+// zero Watched holds zero value of T and notifies about every Store
+type Watched[T any] struct {
+	mu    sync.Mutex
+	v     atomic.Pointer[T]
+	equal func(a, b T) bool
+	// changed is closed on next change to wake up WaitFor calls
+	changed chan struct{}
+	subs    map[*Subscription[T]]struct{}
+}
+
+// Subscription receives new values of Watched
+type Subscription[T any] struct {
+	// C receives new values. Updates are coalesced: if subscriber did not receive previous value
+	// it is replaced by the latest one. C is closed by Close
+	C <-chan T
+	c chan T
+	w *Watched[T]
+}
+
+// Close unsubscribes from changes and closes C
+func (s *Subscription[T]) Close() {
+	s.w.mu.Lock()
+	defer s.w.mu.Unlock()
+	if _, ok := s.w.subs[s]; ok {
+		delete(s.w.subs, s)
+		close(s.c)
+	}
+}
+
+// NewWatched returns Watched with initial value `t`
+// optional argument `equal` suppresses notifications when new value is equal to the old one
+func NewWatched[T any](t T, equal ...func(a, b T) bool) *Watched[T] {
+	w := &Watched[T]{}
+	if len(equal) > 0 {
+		w.equal = equal[0]
+	}
+	w.v.Store(&t)
+	return w
+}
+
+// Load returns current value
+func (w *Watched[T]) Load() T {
+	if p := w.v.Load(); p != nil {
+		return *p
+	}
+	var t T
+	return t
+}
+
+// Store sets new value and notifies subscribers
+func (w *Watched[T]) Store(t T) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.set(t)
+}
+
+// Update atomically sets value to result of `f` applied to current value and returns new value
+func (w *Watched[T]) Update(f func(T) T) T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t := f(w.Load())
+	w.set(t)
+	return t
+}
+
+// set stores value and notifies subscribers. Must be called with mu held
+func (w *Watched[T]) set(t T) {
+	if w.equal != nil && w.equal(w.Load(), t) {
+		return
+	}
+	w.v.Store(&t)
+	if w.changed != nil {
+		close(w.changed)
+		w.changed = nil
+	}
+	for s := range w.subs {
+		// drop value subscriber has not received yet, latest wins
+		select {
+		case <-s.c:
+		default:
+		}
+		s.c <- t
+	}
+}
+
+// Subscribe returns Subscription receiving values set after this call
+// use Load to get current value, Close subscription when it is not needed anymore
+func (w *Watched[T]) Subscribe() *Subscription[T] {
+	c := make(chan T, 1)
+	s := &Subscription[T]{
+		C: c,
+		c: c,
+		w: w,
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.subs == nil {
+		w.subs = make(map[*Subscription[T]]struct{})
+	}
+	w.subs[s] = struct{}{}
+	return s
+}
+
+// SubscribeFunc calls `f` in separate goroutine with new values (updates are coalesced same as in Subscribe)
+// returned func unsubscribes, `f` may be called once more after it returns
+func (w *Watched[T]) SubscribeFunc(f func(T)) (unsubscribe func()) {
+	s := w.Subscribe()
+	go func() {
+		for t := range s.C {
+			f(t)
+		}
+	}()
+	return s.Close
+}
+
+// WaitFor blocks until value satisfies `pred` or `ctx` is done and returns the value
+// `pred` is checked with current value first, so WaitFor returns immediately if it is already satisfied
+// intermediate values may be skipped if value changes faster than WaitFor checks it
+func (w *Watched[T]) WaitFor(ctx context.Context, pred func(T) bool) (T, error) {
+	for {
+		w.mu.Lock()
+		t := w.Load()
+		if w.changed == nil {
+			w.changed = make(chan struct{})
+		}
+		changed := w.changed
+		w.mu.Unlock()
+		if pred(t) {
+			return t, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return t, ctx.Err()
+		}
+	}
+}
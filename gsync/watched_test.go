@@ -0,0 +1,116 @@
+package gsync_test
+
+import (
+	"context"
+	"gtools/gsync"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatched_Simple(t *testing.T) {
+	w := gsync.NewWatched(1)
+	assert.Equal(t, 1, w.Load())
+	w.Store(2)
+	assert.Equal(t, 3, w.Update(func(i int) int { return i + 1 }))
+	assert.Equal(t, 3, w.Load())
+
+	var zero gsync.Watched[string]
+	assert.Equal(t, "", zero.Load())
+	zero.Store("a")
+	assert.Equal(t, "a", zero.Load())
+}
+
+func TestWatched_Subscribe(t *testing.T) {
+	w := gsync.NewWatched(0)
+	s := w.Subscribe()
+	w.Store(1)
+	assert.Equal(t, 1, <-s.C)
+
+	// rapid updates are coalesced
+	for i := 2; i <= 10; i++ {
+		w.Store(i)
+	}
+	assert.Equal(t, 10, <-s.C)
+	select {
+	case v := <-s.C:
+		t.Fatalf("unexpected value %d", v)
+	default:
+	}
+
+	s.Close()
+	s.Close()
+	w.Store(11)
+	_, ok := <-s.C
+	assert.False(t, ok)
+}
+
+func TestWatched_Equal(t *testing.T) {
+	w := gsync.NewWatched([]int{1}, func(a, b []int) bool {
+		return len(a) == len(b)
+	})
+	s := w.Subscribe()
+	defer s.Close()
+	w.Store([]int{2})
+	assert.Equal(t, []int{1}, w.Load())
+	w.Update(func(v []int) []int { return append(v, 2) })
+	assert.Equal(t, []int{1, 2}, <-s.C)
+}
+
+func TestWatched_SubscribeFunc(t *testing.T) {
+	w := gsync.NewWatched("")
+	mu := sync.Mutex{}
+	var got []string
+	received := make(chan struct{}, 10)
+	unsubscribe := w.SubscribeFunc(func(s string) {
+		mu.Lock()
+		got = append(got, s)
+		mu.Unlock()
+		received <- struct{}{}
+	})
+	w.Store("a")
+	<-received
+	w.Store("b")
+	<-received
+	unsubscribe()
+	w.Store("c")
+	time.Sleep(5 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"a", "b"}, got)
+}
+
+func TestWatched_WaitFor(t *testing.T) {
+	ctx := context.Background()
+	w := gsync.NewWatched(0)
+	v, err := w.WaitFor(ctx, func(i int) bool { return i == 0 })
+	assert.NoError(t, err)
+	assert.Equal(t, 0, v)
+
+	go func() {
+		for i := 1; i <= 5; i++ {
+			time.Sleep(time.Millisecond)
+			w.Update(func(i int) int { return i + 1 })
+		}
+	}()
+	v, err = w.WaitFor(ctx, func(i int) bool { return i >= 5 })
+	assert.NoError(t, err)
+	assert.Equal(t, 5, v)
+
+	timeout, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	v, err = w.WaitFor(timeout, func(i int) bool { return i > 5 })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 5, v)
+}
+
+func BenchmarkWatched_Load(b *testing.B) {
+	w := gsync.NewWatched(map[string]int{"a": 1})
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = w.Load()["a"]
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package opt
+
+// Option is an optional value: it either contains value (Some) or not (None)
+// zero Option is None
+type Option[T any] struct {
+	value T
+	some  bool
+}
+
+// Some returns Option with value `t`
+func Some[T any](t T) Option[T] {
+	return Option[T]{
+		value: t,
+		some:  true,
+	}
+}
+
+// None returns empty Option
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// Of returns Some(t) if ok is true and None otherwise
+// useful to convert results of functions like `v, ok := m[k]`
+func Of[T any](t T, ok bool) Option[T] {
+	if !ok {
+		return None[T]()
+	}
+	return Some(t)
+}
+
+// FromPtr returns None if `t` is nil and Some with pointed value otherwise
+func FromPtr[T any](t *T) Option[T] {
+	if t == nil {
+		return None[T]()
+	}
+	return Some(*t)
+}
+
+// IsSome returns true if Option contains value
+func (o Option[T]) IsSome() bool {
+	return o.some
+}
+
+// IsNone returns true if Option is empty
+func (o Option[T]) IsNone() bool {
+	return !o.some
+}
+
+// Get returns value and true if Option contains value and zero value and false otherwise
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.some
+}
+
+// Unwrap returns value of Option
+// panics if Option is None
+func (o Option[T]) Unwrap() T {
+	if !o.some {
+		panic("opt: Unwrap called on None")
+	}
+	return o.value
+}
+
+// OrElse returns value of Option or `t` if Option is None
+func (o Option[T]) OrElse(t T) T {
+	if !o.some {
+		return t
+	}
+	return o.value
+}
+
+// OrElseGet returns value of Option or result of `f` call if Option is None
+func (o Option[T]) OrElseGet(f func() T) T {
+	if !o.some {
+		return f()
+	}
+	return o.value
+}
+
+// Ptr returns pointer to copy of value or nil if Option is None
+func (o Option[T]) Ptr() *T {
+	if !o.some {
+		return nil
+	}
+	v := o.value
+	return &v
+}
+
+// Filter returns Option itself if it contains value satisfying `predicate` and None otherwise
+func (o Option[T]) Filter(predicate func(T) bool) Option[T] {
+	if o.some && predicate(o.value) {
+		return o
+	}
+	return None[T]()
+}
+
+// Map returns Some with result of `f` called on value or None if `o` is None
+// (it is function but not method because methods can not have type parameters)
+func Map[T any, K any](o Option[T], f func(T) K) Option[K] {
+	if !o.some {
+		return None[K]()
+	}
+	return Some(f(o.value))
+}
+
+// AndThen same as Map but `f` returns Option itself
+func AndThen[T any, K any](o Option[T], f func(T) Option[K]) Option[K] {
+	if !o.some {
+		return None[K]()
+	}
+	return f(o.value)
+}
@@ -0,0 +1,79 @@
+package opt_test
+
+import (
+	"gtools/opt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOption_Some(t *testing.T) {
+	o := opt.Some(42)
+	assert.True(t, o.IsSome())
+	assert.False(t, o.IsNone())
+	assert.Equal(t, 42, o.Unwrap())
+	assert.Equal(t, 42, o.OrElse(1))
+	assert.Equal(t, 42, o.OrElseGet(func() int {
+		return 1
+	}))
+	v, ok := o.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+	assert.Equal(t, 42, *o.Ptr())
+}
+
+func TestOption_None(t *testing.T) {
+	f := func(o opt.Option[int]) {
+		assert.False(t, o.IsSome())
+		assert.True(t, o.IsNone())
+		assert.Panics(t, func() {
+			o.Unwrap()
+		})
+		assert.Equal(t, 1, o.OrElse(1))
+		assert.Equal(t, 2, o.OrElseGet(func() int {
+			return 2
+		}))
+		v, ok := o.Get()
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+		assert.Nil(t, o.Ptr())
+	}
+	f(opt.None[int]())
+	f(opt.Option[int]{})
+	f(opt.Of(5, false))
+	f(opt.FromPtr[int](nil))
+}
+
+func TestOption_Constructors(t *testing.T) {
+	a := 5
+	assert.Equal(t, opt.Some(5), opt.FromPtr(&a))
+	assert.Equal(t, opt.Some(5), opt.Of(5, true))
+	m := map[string]int{"a": 1}
+	v, ok := m["a"]
+	assert.Equal(t, opt.Some(1), opt.Of(v, ok))
+	v, ok = m["b"]
+	assert.Equal(t, opt.None[int](), opt.Of(v, ok))
+}
+
+func TestOption_Map(t *testing.T) {
+	assert.Equal(t, opt.Some("42"), opt.Map(opt.Some(42), strconv.Itoa))
+	assert.Equal(t, opt.None[string](), opt.Map(opt.None[int](), strconv.Itoa))
+
+	parse := func(s string) opt.Option[int] {
+		v, err := strconv.Atoi(s)
+		return opt.Of(v, err == nil)
+	}
+	assert.Equal(t, opt.Some(42), opt.AndThen(opt.Some("42"), parse))
+	assert.Equal(t, opt.None[int](), opt.AndThen(opt.Some("x"), parse))
+	assert.Equal(t, opt.None[int](), opt.AndThen(opt.None[string](), parse))
+}
+
+func TestOption_Filter(t *testing.T) {
+	even := func(i int) bool {
+		return i%2 == 0
+	}
+	assert.Equal(t, opt.Some(2), opt.Some(2).Filter(even))
+	assert.Equal(t, opt.None[int](), opt.Some(1).Filter(even))
+	assert.Equal(t, opt.None[int](), opt.None[int]().Filter(even))
+}
@@ -0,0 +1,84 @@
+package result
+
+import "fmt"
+
+// Result is a value or an error of the operation that produced it
+// zero Result is Ok with zero value
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns successful Result with value `t`
+func Ok[T any](t T) Result[T] {
+	return Result[T]{value: t}
+}
+
+// Err returns failed Result with error `err`
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// Of returns Result from pair of value and error (as most go functions returns)
+//
+//	r := result.Of(strconv.Atoi("42"))
+func Of[T any](t T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(t)
+}
+
+// IsOk returns true if Result has no error
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr returns true if Result has error
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get returns value and error of Result
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns error of Result (nil if Result is Ok)
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap returns value of Result
+// panics if Result has error
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(fmt.Sprintf("result: Unwrap called on error: %v", r.err))
+	}
+	return r.value
+}
+
+// UnwrapOr returns value of Result or `t` if Result has error
+func (r Result[T]) UnwrapOr(t T) T {
+	if r.err != nil {
+		return t
+	}
+	return r.value
+}
+
+// Map returns Ok with result of `f` called on value or `r` error
+// (it is function but not method because methods can not have type parameters)
+func Map[T any, K any](r Result[T], f func(T) K) Result[K] {
+	if r.err != nil {
+		return Err[K](r.err)
+	}
+	return Ok(f(r.value))
+}
+
+// AndThen same as Map but `f` may fail
+func AndThen[T any, K any](r Result[T], f func(T) (K, error)) Result[K] {
+	if r.err != nil {
+		return Err[K](r.err)
+	}
+	return Of(f(r.value))
+}
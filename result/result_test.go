@@ -0,0 +1,52 @@
+package result_test
+
+import (
+	"errors"
+	"gtools/result"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTest = errors.New("test error")
+
+func TestResult_Ok(t *testing.T) {
+	r := result.Ok(42)
+	assert.True(t, r.IsOk())
+	assert.False(t, r.IsErr())
+	assert.NoError(t, r.Err())
+	assert.Equal(t, 42, r.Unwrap())
+	assert.Equal(t, 42, r.UnwrapOr(1))
+	v, err := r.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+}
+
+func TestResult_Err(t *testing.T) {
+	r := result.Err[int](errTest)
+	assert.False(t, r.IsOk())
+	assert.True(t, r.IsErr())
+	assert.ErrorIs(t, r.Err(), errTest)
+	assert.Panics(t, func() {
+		r.Unwrap()
+	})
+	assert.Equal(t, 1, r.UnwrapOr(1))
+	_, err := r.Get()
+	assert.ErrorIs(t, err, errTest)
+}
+
+func TestResult_Of(t *testing.T) {
+	assert.Equal(t, result.Ok(42), result.Of(strconv.Atoi("42")))
+	assert.True(t, result.Of(strconv.Atoi("x")).IsErr())
+	assert.Equal(t, result.Err[int](errTest), result.Of(5, errTest))
+}
+
+func TestResult_Map(t *testing.T) {
+	assert.Equal(t, result.Ok("42"), result.Map(result.Ok(42), strconv.Itoa))
+	assert.Equal(t, result.Err[string](errTest), result.Map(result.Err[int](errTest), strconv.Itoa))
+
+	assert.Equal(t, result.Ok(42), result.AndThen(result.Ok("42"), strconv.Atoi))
+	assert.True(t, result.AndThen(result.Ok("x"), strconv.Atoi).IsErr())
+	assert.Equal(t, result.Err[int](errTest), result.AndThen(result.Err[string](errTest), strconv.Atoi))
+}